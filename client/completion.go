@@ -0,0 +1,61 @@
+package client
+
+import "context"
+
+// CompletionRef identifies what completion/complete suggests values for:
+// a prompt or a resource template
+type CompletionRef map[string]interface{}
+
+// PromptRef refers to the arguments of a prompt
+func PromptRef(name string) CompletionRef {
+	return CompletionRef{"type": "ref/prompt", "name": name}
+}
+
+// ResourceTemplateRef refers to the variables of a resource template
+func ResourceTemplateRef(uriTemplate string) CompletionRef {
+	return CompletionRef{"type": "ref/resource", "uri": uriTemplate}
+}
+
+// Completion is the result of completion/complete
+type Completion struct {
+	Values []string `json:"values"`
+	// Total is the number of matches the server knows of, if it said
+	Total   int  `json:"total,omitempty"`
+	HasMore bool `json:"hasMore,omitempty"`
+}
+
+// CanComplete reports whether the server supports completion/complete.
+// The completions capability was only introduced in 2025-03-26, so older
+// servers are given the benefit of the doubt.
+func (s *Session) CanComplete() bool {
+	if s.ProtocolVersion() < ProtocolVersion20250326 {
+		return true
+	}
+	return s.HasCapability("completions")
+}
+
+// Complete asks the server for values of argument that start with value.
+// arguments holds the values entered so far for the other arguments; it is
+// only sent to servers that understand it.
+func (s *Session) Complete(ctx context.Context, ref CompletionRef, argument, value string, arguments map[string]string) (*Completion, error) {
+	params := map[string]interface{}{
+		"ref": ref,
+		"argument": map[string]string{
+			"name":  argument,
+			"value": value,
+		},
+	}
+	if len(arguments) > 0 && s.Features().CompletionContext {
+		params["context"] = map[string]interface{}{
+			"arguments": arguments,
+		}
+	}
+
+	var result struct {
+		Completion Completion `json:"completion"`
+	}
+	if err := s.call(ctx, "completion/complete", params, &result); err != nil {
+		return nil, err
+	}
+	return &result.Completion, nil
+}
@@ -0,0 +1,92 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+)
+
+// Actions a user can take on an elicitation request
+const (
+	ElicitAccept  = "accept"
+	ElicitDecline = "decline"
+	ElicitCancel  = "cancel"
+)
+
+// ElicitRequest holds the params of elicitation/create. RequestedSchema
+// is a flat object schema of primitive properties; Properties lists their
+// names in the order the server sent them, so forms can follow it.
+type ElicitRequest struct {
+	Message         string                 `json:"message"`
+	RequestedSchema map[string]interface{} `json:"requestedSchema"`
+	Properties      []string               `json:"-"`
+}
+
+func (r *ElicitRequest) UnmarshalJSON(data []byte) error {
+	type plain ElicitRequest
+	if err := json.Unmarshal(data, (*plain)(r)); err != nil {
+		return err
+	}
+
+	var raw struct {
+		RequestedSchema struct {
+			Properties json.RawMessage `json:"properties"`
+		} `json:"requestedSchema"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	r.Properties = objectKeys(raw.RequestedSchema.Properties)
+	return nil
+}
+
+// objectKeys returns the keys of a JSON object in document order
+func objectKeys(data json.RawMessage) []string {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return nil
+	}
+
+	var keys []string
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return keys
+		}
+		key, _ := tok.(string)
+		keys = append(keys, key)
+
+		var skip json.RawMessage
+		if err := dec.Decode(&skip); err != nil {
+			return keys
+		}
+	}
+	return keys
+}
+
+// ElicitResult is the client's answer to elicitation/create. Content is
+// only set when the user accepted.
+type ElicitResult struct {
+	Action  string                 `json:"action"`
+	Content map[string]interface{} `json:"content,omitempty"`
+}
+
+// ElicitationHandler asks the user for the information the server wants
+type ElicitationHandler func(ctx context.Context, req *ElicitRequest) (*ElicitResult, error)
+
+// SetElicitationHandler routes elicitation/create to fn and makes the
+// client advertise the elicitation capability. A nil fn removes the handler.
+func (s *Session) SetElicitationHandler(fn ElicitationHandler) {
+	if fn == nil {
+		s.HandleRequest("elicitation/create", nil)
+		return
+	}
+
+	s.HandleRequest("elicitation/create", func(ctx context.Context, params json.RawMessage) (interface{}, error) {
+		var req ElicitRequest
+		if err := decodeParams(params, &req); err != nil {
+			return nil, err
+		}
+		return fn(ctx, &req)
+	})
+}
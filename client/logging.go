@@ -0,0 +1,55 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// LoggingLevels are the syslog severities MCP uses, least severe first
+var LoggingLevels = []string{
+	"debug",
+	"info",
+	"notice",
+	"warning",
+	"error",
+	"critical",
+	"alert",
+	"emergency",
+}
+
+// IsLoggingLevel reports whether level is one of LoggingLevels
+func IsLoggingLevel(level string) bool {
+	for _, l := range LoggingLevels {
+		if l == level {
+			return true
+		}
+	}
+	return false
+}
+
+// LogMessage is a notifications/message sent by the server
+type LogMessage struct {
+	Level  string          `json:"level"`
+	Logger string          `json:"logger,omitempty"`
+	Data   json.RawMessage `json:"data"`
+}
+
+// SetLoggingLevel asks the server to send log messages at level and above
+func (s *Session) SetLoggingLevel(ctx context.Context, level string) error {
+	params := map[string]interface{}{
+		"level": level,
+	}
+	return s.call(ctx, "logging/setLevel", params, nil)
+}
+
+// OnLogMessage registers fn for every notifications/message. The returned
+// function removes the handler.
+func (s *Session) OnLogMessage(fn func(LogMessage)) (remove func()) {
+	return s.OnNotification("notifications/message", func(raw json.RawMessage) {
+		var msg LogMessage
+		if json.Unmarshal(raw, &msg) != nil {
+			return
+		}
+		fn(msg)
+	})
+}
@@ -0,0 +1,63 @@
+package client
+
+import "context"
+
+// Ping checks that the server is alive
+func (s *Session) Ping(ctx context.Context) error {
+	return s.call(ctx, "ping", nil, nil)
+}
+
+// ListTools returns one page of tools/list, starting at cursor
+func (s *Session) ListTools(ctx context.Context, cursor string) (*ListResult[Tool], error) {
+	return s.ListAllTools(ctx, PageOptions{Cursor: cursor, NoFollow: true})
+}
+
+// CallTool invokes a tool with the given arguments
+func (s *Session) CallTool(ctx context.Context, name string, args map[string]interface{}) (*CallToolResult, error) {
+	return s.CallToolWithProgress(ctx, name, args, nil)
+}
+
+// ListResources returns one page of resources/list, starting at cursor
+func (s *Session) ListResources(ctx context.Context, cursor string) (*ListResult[Resource], error) {
+	return s.ListAllResources(ctx, PageOptions{Cursor: cursor, NoFollow: true})
+}
+
+// ReadResource fetches the contents of a resource
+func (s *Session) ReadResource(ctx context.Context, uri string) (*ReadResourceResult, error) {
+	params := map[string]interface{}{
+		"uri": uri,
+	}
+
+	var result ReadResourceResult
+	if err := s.call(ctx, "resources/read", params, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// ListResourceTemplates returns one page of resources/templates/list,
+// starting at cursor
+func (s *Session) ListResourceTemplates(ctx context.Context, cursor string) (*ListResult[ResourceTemplate], error) {
+	return s.ListAllResourceTemplates(ctx, PageOptions{Cursor: cursor, NoFollow: true})
+}
+
+// ListPrompts returns one page of prompts/list, starting at cursor
+func (s *Session) ListPrompts(ctx context.Context, cursor string) (*ListResult[Prompt], error) {
+	return s.ListAllPrompts(ctx, PageOptions{Cursor: cursor, NoFollow: true})
+}
+
+// GetPrompt renders a prompt. args may be nil for prompts without arguments.
+func (s *Session) GetPrompt(ctx context.Context, name string, args map[string]interface{}) (*GetPromptResult, error) {
+	params := map[string]interface{}{
+		"name": name,
+	}
+	if len(args) > 0 {
+		params["arguments"] = args
+	}
+
+	var result GetPromptResult
+	if err := s.call(ctx, "prompts/get", params, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
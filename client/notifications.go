@@ -0,0 +1,71 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/jkeresman01/mcp-client/transport"
+)
+
+// NotificationHandler receives the params of a server notification
+type NotificationHandler func(params json.RawMessage)
+
+// OnNotification registers fn for a notification method such as
+// "notifications/message". The returned function removes the handler.
+func (s *Session) OnNotification(method string, fn NotificationHandler) (remove func()) {
+	s.handlersMu.Lock()
+	defer s.handlersMu.Unlock()
+
+	id := s.nextHandler
+	s.nextHandler++
+
+	if s.notificationHandlers[method] == nil {
+		s.notificationHandlers[method] = map[int]NotificationHandler{}
+	}
+	s.notificationHandlers[method][id] = fn
+
+	return func() {
+		s.handlersMu.Lock()
+		defer s.handlersMu.Unlock()
+		delete(s.notificationHandlers[method], id)
+	}
+}
+
+// startListening runs the transport's Listen loop until the session is
+// closed, so notifications are seen while requests are in flight
+func (s *Session) startListening() {
+	s.listenOnce.Do(func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		s.stopListening = cancel
+
+		go s.transport.Listen(ctx, func(msg transport.RPCMessage) {
+			s.handleMessage(ctx, msg)
+		})
+	})
+}
+
+func (s *Session) handleMessage(ctx context.Context, msg transport.RPCMessage) {
+	s.traceMessage("<-", msg)
+
+	if msg.IsRequest() {
+		go s.handleRequest(ctx, msg)
+		return
+	}
+	if !msg.IsNotification() {
+		return
+	}
+	if msg.Method == "notifications/cancelled" {
+		s.cancelRequest(msg.Params)
+	}
+
+	s.handlersMu.Lock()
+	handlers := make([]NotificationHandler, 0, len(s.notificationHandlers[msg.Method]))
+	for _, h := range s.notificationHandlers[msg.Method] {
+		handlers = append(handlers, h)
+	}
+	s.handlersMu.Unlock()
+
+	for _, h := range handlers {
+		h(msg.Params)
+	}
+}
@@ -0,0 +1,90 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// PageOptions controls how paginated list methods follow nextCursor
+type PageOptions struct {
+	// Cursor starts listing from a cursor returned earlier
+	Cursor string
+	// NoFollow fetches a single page instead of following nextCursor
+	NoFollow bool
+	// Limit stops following once at least Limit items were collected.
+	// Pages are never split, so more items may be returned. Zero means no
+	// limit.
+	Limit int
+}
+
+// ListResult is the merged result of one or more pages
+type ListResult[T any] struct {
+	Items []T
+	// NextCursor is set when the server has more pages we did not fetch
+	NextCursor string
+	Pages      int
+}
+
+// ListAllTools follows tools/list pagination
+func (s *Session) ListAllTools(ctx context.Context, opts PageOptions) (*ListResult[Tool], error) {
+	return listPages[Tool](ctx, s, "tools/list", "tools", opts)
+}
+
+// ListAllResources follows resources/list pagination
+func (s *Session) ListAllResources(ctx context.Context, opts PageOptions) (*ListResult[Resource], error) {
+	return listPages[Resource](ctx, s, "resources/list", "resources", opts)
+}
+
+// ListAllResourceTemplates follows resources/templates/list pagination
+func (s *Session) ListAllResourceTemplates(ctx context.Context, opts PageOptions) (*ListResult[ResourceTemplate], error) {
+	return listPages[ResourceTemplate](ctx, s, "resources/templates/list", "resourceTemplates", opts)
+}
+
+// ListAllPrompts follows prompts/list pagination
+func (s *Session) ListAllPrompts(ctx context.Context, opts PageOptions) (*ListResult[Prompt], error) {
+	return listPages[Prompt](ctx, s, "prompts/list", "prompts", opts)
+}
+
+func listPages[T any](ctx context.Context, s *Session, method, key string, opts PageOptions) (*ListResult[T], error) {
+	result := &ListResult[T]{Items: []T{}}
+	seen := map[string]bool{}
+	cursor := opts.Cursor
+
+	for {
+		params := map[string]interface{}{}
+		if cursor != "" {
+			params["cursor"] = cursor
+		}
+
+		var page map[string]json.RawMessage
+		if err := s.call(ctx, method, params, &page); err != nil {
+			return nil, err
+		}
+		result.Pages++
+
+		var items []T
+		if raw, ok := page[key]; ok {
+			if err := json.Unmarshal(raw, &items); err != nil {
+				return nil, fmt.Errorf("failed to parse %s result: %w", method, err)
+			}
+		}
+		result.Items = append(result.Items, items...)
+
+		var next string
+		if raw, ok := page["nextCursor"]; ok {
+			json.Unmarshal(raw, &next)
+		}
+		result.NextCursor = next
+		if next == "" || opts.NoFollow || (opts.Limit > 0 && len(result.Items) >= opts.Limit) {
+			return result, nil
+		}
+
+		// A server handing out the same cursor again would loop forever
+		if seen[next] {
+			return nil, fmt.Errorf("%s returned cursor %q more than once", method, next)
+		}
+		seen[next] = true
+		cursor = next
+	}
+}
@@ -0,0 +1,53 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/jkeresman01/mcp-client/transport"
+)
+
+// Progress is a notifications/progress update for a request we sent
+type Progress struct {
+	Progress float64
+	// Total is zero when the server does not know how much work is left
+	Total   float64
+	Message string
+}
+
+// CallToolWithProgress invokes a tool with a progress token attached and
+// reports every notifications/progress for it to onProgress
+func (s *Session) CallToolWithProgress(ctx context.Context, name string, args map[string]interface{}, onProgress func(Progress)) (*CallToolResult, error) {
+	params := map[string]interface{}{
+		"name":      name,
+		"arguments": args,
+	}
+
+	if onProgress != nil {
+		token := transport.StringID(fmt.Sprintf("%s-%d", ClientName, s.nextToken.Add(1)))
+		params["_meta"] = map[string]interface{}{
+			"progressToken": token,
+		}
+
+		remove := s.OnNotification("notifications/progress", func(raw json.RawMessage) {
+			var p struct {
+				ProgressToken transport.ID `json:"progressToken"`
+				Progress      float64      `json:"progress"`
+				Total         float64      `json:"total"`
+				Message       string       `json:"message"`
+			}
+			if json.Unmarshal(raw, &p) != nil || p.ProgressToken != token {
+				return
+			}
+			onProgress(Progress{Progress: p.Progress, Total: p.Total, Message: p.Message})
+		})
+		defer remove()
+	}
+
+	var result CallToolResult
+	if err := s.call(ctx, "tools/call", params, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
@@ -0,0 +1,97 @@
+package client
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MCP protocol revisions the client speaks
+const (
+	ProtocolVersion20241105 = "2024-11-05"
+	ProtocolVersion20250326 = "2025-03-26"
+	ProtocolVersion20250618 = "2025-06-18"
+
+	// LatestProtocolVersion is requested when no version is pinned
+	LatestProtocolVersion = ProtocolVersion20250618
+)
+
+// SupportedProtocolVersions lists the versions the client speaks, newest first
+var SupportedProtocolVersions = []string{
+	ProtocolVersion20250618,
+	ProtocolVersion20250326,
+	ProtocolVersion20241105,
+}
+
+// IsSupportedProtocolVersion reports whether the client speaks version
+func IsSupportedProtocolVersion(version string) bool {
+	for _, v := range SupportedProtocolVersions {
+		if v == version {
+			return true
+		}
+	}
+	return false
+}
+
+// olderProtocolVersions returns the supported versions older than version,
+// newest first, which is the order we fall back in
+func olderProtocolVersions(version string) []string {
+	var older []string
+	for _, v := range SupportedProtocolVersions {
+		if v < version {
+			older = append(older, v)
+		}
+	}
+	return older
+}
+
+// Features describes the protocol behavior that depends on the negotiated
+// version
+type Features struct {
+	// ProtocolVersionHeader: HTTP requests carry MCP-Protocol-Version
+	ProtocolVersionHeader bool
+	// AudioContent: content blocks may have type "audio"
+	AudioContent bool
+	// StructuredOutput: tools may declare outputSchema and return
+	// structuredContent
+	StructuredOutput bool
+	// Elicitation: servers may ask the user for input via elicitation/create
+	Elicitation bool
+	// ResourceLinks: tool results may contain resource_link content
+	ResourceLinks bool
+	// CompletionContext: completion/complete accepts previously entered
+	// arguments as context
+	CompletionContext bool
+}
+
+// FeaturesFor returns the features of a protocol version. Version strings
+// are dates, so they compare chronologically as plain strings.
+func FeaturesFor(version string) Features {
+	return Features{
+		ProtocolVersionHeader: version >= ProtocolVersion20250618,
+		AudioContent:          version >= ProtocolVersion20250326,
+		StructuredOutput:      version >= ProtocolVersion20250618,
+		Elicitation:           version >= ProtocolVersion20250618,
+		ResourceLinks:         version >= ProtocolVersion20250618,
+		CompletionContext:     version >= ProtocolVersion20250618,
+	}
+}
+
+// UnsupportedVersionError means the client and server could not agree on a
+// protocol version
+type UnsupportedVersionError struct {
+	Requested string
+	Offered   string
+}
+
+func (e *UnsupportedVersionError) Error() string {
+	if e.Offered == "" {
+		return fmt.Sprintf("protocol version %q is not supported by this client (supported: %s)",
+			e.Requested, strings.Join(SupportedProtocolVersions, ", "))
+	}
+	if IsSupportedProtocolVersion(e.Offered) {
+		return fmt.Sprintf("requested protocol version %q but the server selected %q",
+			e.Requested, e.Offered)
+	}
+	return fmt.Sprintf("server selected protocol version %q, which this client does not support (supported: %s)",
+		e.Offered, strings.Join(SupportedProtocolVersions, ", "))
+}
@@ -0,0 +1,178 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/jkeresman01/mcp-client/transport"
+)
+
+// respondTimeout bounds sending the answer to a server request
+const respondTimeout = 10 * time.Second
+
+// RequestHandler answers a request the server sent to the client. The
+// result is marshalled as the response; a *transport.RPCError is sent as
+// the error as-is, any other error as an internal error. ctx is cancelled
+// when the server cancels the request or the session is closed.
+type RequestHandler func(ctx context.Context, params json.RawMessage) (interface{}, error)
+
+// HandleRequest registers fn for a server request method such as
+// "sampling/createMessage", replacing any previous handler. A nil fn
+// removes it. Handlers must be registered before Initialize for their
+// capability to be advertised.
+func (s *Session) HandleRequest(method string, fn RequestHandler) {
+	s.handlersMu.Lock()
+	defer s.handlersMu.Unlock()
+
+	if fn == nil {
+		delete(s.requestHandlers, method)
+		return
+	}
+	s.requestHandlers[method] = fn
+}
+
+func (s *Session) hasRequestHandler(method string) bool {
+	s.handlersMu.Lock()
+	defer s.handlersMu.Unlock()
+
+	_, ok := s.requestHandlers[method]
+	return ok
+}
+
+// clientCapabilities advertises the features that have a handler and
+// exist in the protocol version being requested
+func (s *Session) clientCapabilities(version string) map[string]interface{} {
+	caps := map[string]interface{}{}
+
+	if s.hasRequestHandler("roots/list") {
+		caps["roots"] = map[string]interface{}{"listChanged": true}
+	}
+	if s.hasRequestHandler("sampling/createMessage") {
+		caps["sampling"] = map[string]interface{}{}
+	}
+	if s.hasRequestHandler("elicitation/create") && FeaturesFor(version).Elicitation {
+		caps["elicitation"] = map[string]interface{}{}
+	}
+	return caps
+}
+
+// handleRequest answers a server request. It runs on its own goroutine, so
+// a handler that waits on the user doesn't hold up other messages.
+func (s *Session) handleRequest(ctx context.Context, msg transport.RPCMessage) {
+	id := *msg.ID
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	s.handlersMu.Lock()
+	s.inflight[id] = cancel
+	s.handlersMu.Unlock()
+
+	defer func() {
+		s.handlersMu.Lock()
+		delete(s.inflight, id)
+		s.handlersMu.Unlock()
+	}()
+
+	result, err := s.dispatchRequest(ctx, msg.Method, msg.Params)
+
+	// Nobody is waiting for the answer to a cancelled request
+	if ctx.Err() != nil {
+		return
+	}
+
+	resp := transport.RPCResponse{
+		JSONRPC: transport.JSONRPCVersion,
+		ID:      id,
+	}
+	if err == nil {
+		resp.Result, err = json.Marshal(result)
+	}
+	if err != nil {
+		resp.Result = nil
+		resp.Error = toRPCError(err)
+	}
+
+	s.traceMessage("->", resp)
+
+	respondCtx, cancelRespond := context.WithTimeout(context.Background(), respondTimeout)
+	defer cancelRespond()
+	s.transport.Respond(respondCtx, resp)
+}
+
+func (s *Session) dispatchRequest(ctx context.Context, method string, params json.RawMessage) (interface{}, error) {
+	if method == "ping" {
+		return struct{}{}, nil
+	}
+
+	s.handlersMu.Lock()
+	fn := s.requestHandlers[method]
+	s.handlersMu.Unlock()
+
+	// Not advertised to servers on older versions, so not answered either
+	if method == "elicitation/create" && !s.Features().Elicitation {
+		fn = nil
+	}
+
+	if fn == nil {
+		return nil, &transport.RPCError{
+			Code:    transport.CodeMethodNotFound,
+			Message: "Method not found: " + method,
+		}
+	}
+
+	result, err := fn(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		return struct{}{}, nil
+	}
+	return result, nil
+}
+
+// cancelRequest stops the handler of a request the server cancelled
+func (s *Session) cancelRequest(params json.RawMessage) {
+	var p struct {
+		RequestID transport.ID `json:"requestId"`
+	}
+	if err := json.Unmarshal(params, &p); err != nil {
+		return
+	}
+
+	s.handlersMu.Lock()
+	cancel := s.inflight[p.RequestID]
+	s.handlersMu.Unlock()
+
+	if cancel != nil {
+		cancel()
+	}
+}
+
+func toRPCError(err error) *transport.RPCError {
+	var rpcErr *transport.RPCError
+	if errors.As(err, &rpcErr) {
+		return rpcErr
+	}
+	return &transport.RPCError{
+		Code:    transport.CodeInternalError,
+		Message: err.Error(),
+	}
+}
+
+// decodeParams unmarshals request params, reporting bad ones to the server
+// as invalid params
+func decodeParams(params json.RawMessage, out interface{}) error {
+	if len(params) == 0 {
+		params = json.RawMessage("{}")
+	}
+	if err := json.Unmarshal(params, out); err != nil {
+		return &transport.RPCError{
+			Code:    transport.CodeInvalidParams,
+			Message: "Invalid params: " + err.Error(),
+		}
+	}
+	return nil
+}
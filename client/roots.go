@@ -0,0 +1,48 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// Root is a directory or file the client exposes to the server
+type Root struct {
+	URI  string `json:"uri"`
+	Name string `json:"name,omitempty"`
+}
+
+// SetRoots replaces the roots served to roots/list. Before Initialize this
+// makes the client advertise the roots capability; afterwards the server
+// is sent notifications/roots/list_changed.
+func (s *Session) SetRoots(ctx context.Context, roots []Root) error {
+	s.handlersMu.Lock()
+	s.roots = append([]Root(nil), roots...)
+	if _, ok := s.requestHandlers["roots/list"]; !ok {
+		s.requestHandlers["roots/list"] = s.listRoots
+	}
+	s.handlersMu.Unlock()
+
+	s.mu.Lock()
+	initialized := s.initialized
+	s.mu.Unlock()
+
+	if !initialized {
+		return nil
+	}
+	return s.Notify(ctx, "notifications/roots/list_changed", nil)
+}
+
+// Roots returns the roots served to roots/list
+func (s *Session) Roots() []Root {
+	s.handlersMu.Lock()
+	defer s.handlersMu.Unlock()
+	return append([]Root(nil), s.roots...)
+}
+
+func (s *Session) listRoots(ctx context.Context, params json.RawMessage) (interface{}, error) {
+	roots := s.Roots()
+	if roots == nil {
+		roots = []Root{}
+	}
+	return map[string]interface{}{"roots": roots}, nil
+}
@@ -0,0 +1,68 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// SamplingMessage is one turn of the conversation the server wants
+// completed. Content is a text, image or audio content block.
+type SamplingMessage struct {
+	Role    string                 `json:"role"`
+	Content map[string]interface{} `json:"content"`
+}
+
+// ModelHint names a model, or a family of models, the server would prefer
+type ModelHint struct {
+	Name string `json:"name,omitempty"`
+}
+
+// ModelPreferences are the server's advisory model requirements. The
+// priorities range from 0 to 1.
+type ModelPreferences struct {
+	Hints                []ModelHint `json:"hints,omitempty"`
+	CostPriority         *float64    `json:"costPriority,omitempty"`
+	SpeedPriority        *float64    `json:"speedPriority,omitempty"`
+	IntelligencePriority *float64    `json:"intelligencePriority,omitempty"`
+}
+
+// CreateMessageRequest holds the params of sampling/createMessage
+type CreateMessageRequest struct {
+	Messages         []SamplingMessage      `json:"messages"`
+	ModelPreferences *ModelPreferences      `json:"modelPreferences,omitempty"`
+	SystemPrompt     string                 `json:"systemPrompt,omitempty"`
+	IncludeContext   string                 `json:"includeContext,omitempty"`
+	Temperature      *float64               `json:"temperature,omitempty"`
+	MaxTokens        int                    `json:"maxTokens"`
+	StopSequences    []string               `json:"stopSequences,omitempty"`
+	Metadata         map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// CreateMessageResult is the client's answer to sampling/createMessage
+type CreateMessageResult struct {
+	Role       string                 `json:"role"`
+	Content    map[string]interface{} `json:"content"`
+	Model      string                 `json:"model"`
+	StopReason string                 `json:"stopReason,omitempty"`
+}
+
+// SamplingHandler generates a message for the server, usually by asking an
+// LLM. Returning a *transport.RPCError rejects the request with that error.
+type SamplingHandler func(ctx context.Context, req *CreateMessageRequest) (*CreateMessageResult, error)
+
+// SetSamplingHandler routes sampling/createMessage to fn and makes the
+// client advertise the sampling capability. A nil fn removes the handler.
+func (s *Session) SetSamplingHandler(fn SamplingHandler) {
+	if fn == nil {
+		s.HandleRequest("sampling/createMessage", nil)
+		return
+	}
+
+	s.HandleRequest("sampling/createMessage", func(ctx context.Context, params json.RawMessage) (interface{}, error) {
+		var req CreateMessageRequest
+		if err := decodeParams(params, &req); err != nil {
+			return nil, err
+		}
+		return fn(ctx, &req)
+	})
+}
@@ -0,0 +1,420 @@
+package client
+
+import "encoding/json"
+
+// Implementation names the client or server software
+type Implementation struct {
+	Name    string                     `json:"name"`
+	Title   string                     `json:"title,omitempty"`
+	Version string                     `json:"version"`
+	Unknown map[string]json.RawMessage `json:"-"`
+}
+
+// ServerCapabilities are the features the server advertised in its
+// initialize result
+type ServerCapabilities struct {
+	Experimental map[string]json.RawMessage `json:"experimental,omitempty"`
+	Logging      *Capability                `json:"logging,omitempty"`
+	Completions  *Capability                `json:"completions,omitempty"`
+	Prompts      *ListChangedCapability     `json:"prompts,omitempty"`
+	Resources    *ResourcesCapability       `json:"resources,omitempty"`
+	Tools        *ListChangedCapability     `json:"tools,omitempty"`
+	Unknown      map[string]json.RawMessage `json:"-"`
+}
+
+// Has reports whether the server advertised a top-level capability such
+// as "tools" or "resources", including ones this client doesn't know
+func (c *ServerCapabilities) Has(name string) bool {
+	switch name {
+	case "experimental":
+		return c.Experimental != nil
+	case "logging":
+		return c.Logging != nil
+	case "completions":
+		return c.Completions != nil
+	case "prompts":
+		return c.Prompts != nil
+	case "resources":
+		return c.Resources != nil
+	case "tools":
+		return c.Tools != nil
+	}
+	_, ok := c.Unknown[name]
+	return ok
+}
+
+// Capability is a capability without settings of its own, like logging
+type Capability struct {
+	Unknown map[string]json.RawMessage `json:"-"`
+}
+
+// ListChangedCapability is a capability whose list can change, announced
+// with a list_changed notification
+type ListChangedCapability struct {
+	ListChanged bool                       `json:"listChanged,omitempty"`
+	Unknown     map[string]json.RawMessage `json:"-"`
+}
+
+// ResourcesCapability also says whether resources/subscribe is supported
+type ResourcesCapability struct {
+	Subscribe   bool                       `json:"subscribe,omitempty"`
+	ListChanged bool                       `json:"listChanged,omitempty"`
+	Unknown     map[string]json.RawMessage `json:"-"`
+}
+
+// Tool is an entry of tools/list. The schemas are kept as decoded JSON so
+// they can be compiled with the jsonschema package.
+type Tool struct {
+	Name         string                     `json:"name"`
+	Title        string                     `json:"title,omitempty"`
+	Description  string                     `json:"description,omitempty"`
+	InputSchema  interface{}                `json:"inputSchema,omitempty"`
+	OutputSchema interface{}                `json:"outputSchema,omitempty"`
+	Annotations  *ToolAnnotations           `json:"annotations,omitempty"`
+	Meta         map[string]interface{}     `json:"_meta,omitempty"`
+	Unknown      map[string]json.RawMessage `json:"-"`
+}
+
+// DisplayName is the title to show for a tool, falling back to its name
+func (t *Tool) DisplayName() string {
+	if t.Title != "" {
+		return t.Title
+	}
+	if t.Annotations != nil && t.Annotations.Title != "" {
+		return t.Annotations.Title
+	}
+	return t.Name
+}
+
+// ToolAnnotations are hints about a tool's behavior. They come from the
+// server and can't be trusted.
+type ToolAnnotations struct {
+	Title           string                     `json:"title,omitempty"`
+	ReadOnlyHint    *bool                      `json:"readOnlyHint,omitempty"`
+	DestructiveHint *bool                      `json:"destructiveHint,omitempty"`
+	IdempotentHint  *bool                      `json:"idempotentHint,omitempty"`
+	OpenWorldHint   *bool                      `json:"openWorldHint,omitempty"`
+	Unknown         map[string]json.RawMessage `json:"-"`
+}
+
+// Annotations tell the client how to use a resource or content block
+type Annotations struct {
+	Audience     []string                   `json:"audience,omitempty"`
+	Priority     *float64                   `json:"priority,omitempty"`
+	LastModified string                     `json:"lastModified,omitempty"`
+	Unknown      map[string]json.RawMessage `json:"-"`
+}
+
+// Resource is an entry of resources/list
+type Resource struct {
+	URI         string                     `json:"uri"`
+	Name        string                     `json:"name"`
+	Title       string                     `json:"title,omitempty"`
+	Description string                     `json:"description,omitempty"`
+	MimeType    string                     `json:"mimeType,omitempty"`
+	Size        *int64                     `json:"size,omitempty"`
+	Annotations *Annotations               `json:"annotations,omitempty"`
+	Meta        map[string]interface{}     `json:"_meta,omitempty"`
+	Unknown     map[string]json.RawMessage `json:"-"`
+}
+
+// ResourceTemplate is an entry of resources/templates/list
+type ResourceTemplate struct {
+	URITemplate string                     `json:"uriTemplate"`
+	Name        string                     `json:"name"`
+	Title       string                     `json:"title,omitempty"`
+	Description string                     `json:"description,omitempty"`
+	MimeType    string                     `json:"mimeType,omitempty"`
+	Annotations *Annotations               `json:"annotations,omitempty"`
+	Meta        map[string]interface{}     `json:"_meta,omitempty"`
+	Unknown     map[string]json.RawMessage `json:"-"`
+}
+
+// ResourceContents is the content of a resource, as text or as a base64
+// blob
+type ResourceContents struct {
+	URI      string                     `json:"uri"`
+	MimeType string                     `json:"mimeType,omitempty"`
+	Text     string                     `json:"text,omitempty"`
+	Blob     string                     `json:"blob,omitempty"`
+	Meta     map[string]interface{}     `json:"_meta,omitempty"`
+	Unknown  map[string]json.RawMessage `json:"-"`
+}
+
+// Prompt is an entry of prompts/list
+type Prompt struct {
+	Name        string                     `json:"name"`
+	Title       string                     `json:"title,omitempty"`
+	Description string                     `json:"description,omitempty"`
+	Arguments   []PromptArgument           `json:"arguments,omitempty"`
+	Meta        map[string]interface{}     `json:"_meta,omitempty"`
+	Unknown     map[string]json.RawMessage `json:"-"`
+}
+
+// PromptArgument is an argument a prompt accepts
+type PromptArgument struct {
+	Name        string                     `json:"name"`
+	Title       string                     `json:"title,omitempty"`
+	Description string                     `json:"description,omitempty"`
+	Required    bool                       `json:"required,omitempty"`
+	Unknown     map[string]json.RawMessage `json:"-"`
+}
+
+// PromptMessage is one message of a rendered prompt
+type PromptMessage struct {
+	Role    string                     `json:"role"`
+	Content ContentBlock               `json:"content"`
+	Unknown map[string]json.RawMessage `json:"-"`
+}
+
+// Content block types
+const (
+	ContentText         = "text"
+	ContentImage        = "image"
+	ContentAudio        = "audio"
+	ContentResource     = "resource"
+	ContentResourceLink = "resource_link"
+)
+
+// ContentBlock is a content block of any type. Which fields are set
+// depends on Type: Text for text, Data and MimeType for image and audio,
+// Resource for an embedded resource, and URI, Name, MimeType and the
+// descriptive fields for a resource link.
+type ContentBlock struct {
+	Type        string                     `json:"type"`
+	Text        string                     `json:"text,omitempty"`
+	Data        string                     `json:"data,omitempty"`
+	Resource    *ResourceContents          `json:"resource,omitempty"`
+	URI         string                     `json:"uri,omitempty"`
+	Name        string                     `json:"name,omitempty"`
+	Title       string                     `json:"title,omitempty"`
+	Description string                     `json:"description,omitempty"`
+	MimeType    string                     `json:"mimeType,omitempty"`
+	Size        *int64                     `json:"size,omitempty"`
+	Annotations *Annotations               `json:"annotations,omitempty"`
+	Meta        map[string]interface{}     `json:"_meta,omitempty"`
+	Unknown     map[string]json.RawMessage `json:"-"`
+}
+
+// CallToolResult is the result of tools/call
+type CallToolResult struct {
+	Content           []ContentBlock             `json:"content"`
+	StructuredContent interface{}                `json:"structuredContent,omitempty"`
+	IsError           bool                       `json:"isError,omitempty"`
+	Meta              map[string]interface{}     `json:"_meta,omitempty"`
+	Unknown           map[string]json.RawMessage `json:"-"`
+}
+
+// ReadResourceResult is the result of resources/read
+type ReadResourceResult struct {
+	Contents []ResourceContents         `json:"contents"`
+	Meta     map[string]interface{}     `json:"_meta,omitempty"`
+	Unknown  map[string]json.RawMessage `json:"-"`
+}
+
+// GetPromptResult is the result of prompts/get
+type GetPromptResult struct {
+	Description string                     `json:"description,omitempty"`
+	Messages    []PromptMessage            `json:"messages"`
+	Meta        map[string]interface{}     `json:"_meta,omitempty"`
+	Unknown     map[string]json.RawMessage `json:"-"`
+}
+
+func (v *Implementation) UnmarshalJSON(data []byte) error {
+	type plain Implementation
+	return decodeKnown(data, (*plain)(v), &v.Unknown)
+}
+
+func (v Implementation) MarshalJSON() ([]byte, error) {
+	type plain Implementation
+	return encodeKnown(plain(v), v.Unknown)
+}
+
+func (v *ServerCapabilities) UnmarshalJSON(data []byte) error {
+	type plain ServerCapabilities
+	return decodeKnown(data, (*plain)(v), &v.Unknown)
+}
+
+func (v ServerCapabilities) MarshalJSON() ([]byte, error) {
+	type plain ServerCapabilities
+	return encodeKnown(plain(v), v.Unknown)
+}
+
+func (v *Capability) UnmarshalJSON(data []byte) error {
+	type plain Capability
+	return decodeKnown(data, (*plain)(v), &v.Unknown)
+}
+
+func (v Capability) MarshalJSON() ([]byte, error) {
+	type plain Capability
+	return encodeKnown(plain(v), v.Unknown)
+}
+
+func (v *ListChangedCapability) UnmarshalJSON(data []byte) error {
+	type plain ListChangedCapability
+	return decodeKnown(data, (*plain)(v), &v.Unknown)
+}
+
+func (v ListChangedCapability) MarshalJSON() ([]byte, error) {
+	type plain ListChangedCapability
+	return encodeKnown(plain(v), v.Unknown)
+}
+
+func (v *ResourcesCapability) UnmarshalJSON(data []byte) error {
+	type plain ResourcesCapability
+	return decodeKnown(data, (*plain)(v), &v.Unknown)
+}
+
+func (v ResourcesCapability) MarshalJSON() ([]byte, error) {
+	type plain ResourcesCapability
+	return encodeKnown(plain(v), v.Unknown)
+}
+
+func (v *Tool) UnmarshalJSON(data []byte) error {
+	type plain Tool
+	return decodeKnown(data, (*plain)(v), &v.Unknown)
+}
+
+func (v Tool) MarshalJSON() ([]byte, error) {
+	type plain Tool
+	return encodeKnown(plain(v), v.Unknown)
+}
+
+func (v *ToolAnnotations) UnmarshalJSON(data []byte) error {
+	type plain ToolAnnotations
+	return decodeKnown(data, (*plain)(v), &v.Unknown)
+}
+
+func (v ToolAnnotations) MarshalJSON() ([]byte, error) {
+	type plain ToolAnnotations
+	return encodeKnown(plain(v), v.Unknown)
+}
+
+func (v *Annotations) UnmarshalJSON(data []byte) error {
+	type plain Annotations
+	return decodeKnown(data, (*plain)(v), &v.Unknown)
+}
+
+func (v Annotations) MarshalJSON() ([]byte, error) {
+	type plain Annotations
+	return encodeKnown(plain(v), v.Unknown)
+}
+
+func (v *Resource) UnmarshalJSON(data []byte) error {
+	type plain Resource
+	return decodeKnown(data, (*plain)(v), &v.Unknown)
+}
+
+func (v Resource) MarshalJSON() ([]byte, error) {
+	type plain Resource
+	return encodeKnown(plain(v), v.Unknown)
+}
+
+func (v *ResourceTemplate) UnmarshalJSON(data []byte) error {
+	type plain ResourceTemplate
+	return decodeKnown(data, (*plain)(v), &v.Unknown)
+}
+
+func (v ResourceTemplate) MarshalJSON() ([]byte, error) {
+	type plain ResourceTemplate
+	return encodeKnown(plain(v), v.Unknown)
+}
+
+func (v *ResourceContents) UnmarshalJSON(data []byte) error {
+	type plain ResourceContents
+	return decodeKnown(data, (*plain)(v), &v.Unknown)
+}
+
+// MarshalJSON always writes text for text contents, even when empty, so
+// they don't lose both text and blob
+func (v ResourceContents) MarshalJSON() ([]byte, error) {
+	type plain ResourceContents
+	data, err := encodeKnown(plain(v), v.Unknown)
+	if err != nil || v.Text != "" || v.Blob != "" {
+		return data, err
+	}
+	return keepEmpty(data, "text"), nil
+}
+
+func (v *Prompt) UnmarshalJSON(data []byte) error {
+	type plain Prompt
+	return decodeKnown(data, (*plain)(v), &v.Unknown)
+}
+
+func (v Prompt) MarshalJSON() ([]byte, error) {
+	type plain Prompt
+	return encodeKnown(plain(v), v.Unknown)
+}
+
+func (v *PromptArgument) UnmarshalJSON(data []byte) error {
+	type plain PromptArgument
+	return decodeKnown(data, (*plain)(v), &v.Unknown)
+}
+
+func (v PromptArgument) MarshalJSON() ([]byte, error) {
+	type plain PromptArgument
+	return encodeKnown(plain(v), v.Unknown)
+}
+
+func (v *PromptMessage) UnmarshalJSON(data []byte) error {
+	type plain PromptMessage
+	return decodeKnown(data, (*plain)(v), &v.Unknown)
+}
+
+func (v PromptMessage) MarshalJSON() ([]byte, error) {
+	type plain PromptMessage
+	return encodeKnown(plain(v), v.Unknown)
+}
+
+func (v *ContentBlock) UnmarshalJSON(data []byte) error {
+	type plain ContentBlock
+	return decodeKnown(data, (*plain)(v), &v.Unknown)
+}
+
+// MarshalJSON always writes the text of a text block and the data of an
+// image or audio block, even when empty, since the protocol requires them
+func (v ContentBlock) MarshalJSON() ([]byte, error) {
+	type plain ContentBlock
+	data, err := encodeKnown(plain(v), v.Unknown)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case v.Type == ContentText && v.Text == "":
+		data = keepEmpty(data, "text")
+	case (v.Type == ContentImage || v.Type == ContentAudio) && v.Data == "":
+		data = keepEmpty(data, "data")
+	}
+	return data, nil
+}
+
+func (v *CallToolResult) UnmarshalJSON(data []byte) error {
+	type plain CallToolResult
+	return decodeKnown(data, (*plain)(v), &v.Unknown)
+}
+
+func (v CallToolResult) MarshalJSON() ([]byte, error) {
+	type plain CallToolResult
+	return encodeKnown(plain(v), v.Unknown)
+}
+
+func (v *ReadResourceResult) UnmarshalJSON(data []byte) error {
+	type plain ReadResourceResult
+	return decodeKnown(data, (*plain)(v), &v.Unknown)
+}
+
+func (v ReadResourceResult) MarshalJSON() ([]byte, error) {
+	type plain ReadResourceResult
+	return encodeKnown(plain(v), v.Unknown)
+}
+
+func (v *GetPromptResult) UnmarshalJSON(data []byte) error {
+	type plain GetPromptResult
+	return decodeKnown(data, (*plain)(v), &v.Unknown)
+}
+
+func (v GetPromptResult) MarshalJSON() ([]byte, error) {
+	type plain GetPromptResult
+	return encodeKnown(plain(v), v.Unknown)
+}
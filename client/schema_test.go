@@ -0,0 +1,102 @@
+package client
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestToolRoundTrip(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{
+			`{"name":"search","inputSchema":{"type":"object"}}`,
+			`{"name":"search","inputSchema":{"type":"object"}}`,
+		},
+		{
+			`{"name":"search","icons":[{"src":"a.png"}],"annotations":{"readOnlyHint":true,"future":1},"x-vendor":{"a":1}}`,
+			`{"name":"search","annotations":{"readOnlyHint":true,"future":1},"icons":[{"src":"a.png"}],"x-vendor":{"a":1}}`,
+		},
+		// encoding/json matches keys case-insensitively, so these aren't unknown
+		{
+			`{"Name":"search","TITLE":"Search"}`,
+			`{"name":"search","title":"Search"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		testRoundTrip[Tool](t, tt.in, tt.want)
+	}
+}
+
+func TestContentBlockRoundTrip(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{`{"type":"text","text":"hi"}`, `{"type":"text","text":"hi"}`},
+		{`{"type":"text","text":""}`, `{"type":"text","text":""}`},
+		{`{"type":"text","text":"","extra":true}`, `{"type":"text","extra":true,"text":""}`},
+		{`{"type":"image","data":"","mimeType":"image/png"}`, `{"type":"image","mimeType":"image/png","data":""}`},
+		{`{"type":"audio","data":"AAAA","mimeType":"audio/wav"}`, `{"type":"audio","data":"AAAA","mimeType":"audio/wav"}`},
+		{
+			`{"type":"resource","resource":{"uri":"file:///a","text":"","extra":1}}`,
+			`{"type":"resource","resource":{"uri":"file:///a","extra":1,"text":""}}`,
+		},
+		{
+			`{"type":"resource_link","uri":"file:///a","name":"a","size":3}`,
+			`{"type":"resource_link","uri":"file:///a","name":"a","size":3}`,
+		},
+		{`{"type":"video","url":"https://example.com/v"}`, `{"type":"video","url":"https://example.com/v"}`},
+		{`{"Type":"text","URI":"file:///a","Text":"hi"}`, `{"type":"text","text":"hi","uri":"file:///a"}`},
+	}
+
+	for _, tt := range tests {
+		testRoundTrip[ContentBlock](t, tt.in, tt.want)
+	}
+}
+
+func TestResourceContentsRoundTrip(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{`{"uri":"file:///a","text":"hi"}`, `{"uri":"file:///a","text":"hi"}`},
+		{`{"uri":"file:///a","text":""}`, `{"uri":"file:///a","text":""}`},
+		{`{"uri":"file:///a","blob":"AAAA","mimeType":"image/png"}`, `{"uri":"file:///a","mimeType":"image/png","blob":"AAAA"}`},
+		{`{"uri":"file:///a","text":"","_meta":{"k":"v"},"extra":[1]}`, `{"uri":"file:///a","_meta":{"k":"v"},"extra":[1],"text":""}`},
+		{`{"URI":"file:///a","text":"hi"}`, `{"uri":"file:///a","text":"hi"}`},
+	}
+
+	for _, tt := range tests {
+		testRoundTrip[ResourceContents](t, tt.in, tt.want)
+	}
+}
+
+// testRoundTrip decodes in as a T, checks it encodes to want, and checks
+// that decoding and encoding that again changes nothing
+func testRoundTrip[T any](t *testing.T, in, want string) {
+	t.Helper()
+
+	var v T
+	if err := json.Unmarshal([]byte(in), &v); err != nil {
+		t.Errorf("Unmarshal(%s): %v", in, err)
+		return
+	}
+	out, err := json.Marshal(v)
+	if err != nil {
+		t.Errorf("Marshal(%s): %v", in, err)
+		return
+	}
+	if string(out) != want {
+		t.Errorf("round trip of %s = %s, want %s", in, out, want)
+		return
+	}
+
+	var again T
+	if err := json.Unmarshal(out, &again); err != nil {
+		t.Errorf("Unmarshal(%s): %v", out, err)
+		return
+	}
+	if out2, _ := json.Marshal(again); string(out2) != string(out) {
+		t.Errorf("second round trip of %s = %s", out, out2)
+	}
+}
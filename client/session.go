@@ -0,0 +1,266 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/jkeresman01/mcp-client/transport"
+)
+
+const (
+	ClientName    = "mcp-client"
+	ClientVersion = "0.2.0"
+)
+
+// Session is a connection to an MCP server. It runs the initialize
+// handshake, allocates request IDs and remembers what the server told us
+// about itself.
+type Session struct {
+	transport     transport.Transport
+	nextID        atomic.Int64
+	trace         func(direction string, msg interface{})
+	pinnedVersion string
+	nextToken     atomic.Int64
+
+	listenOnce    sync.Once
+	stopListening context.CancelFunc
+
+	handlersMu           sync.Mutex
+	notificationHandlers map[string]map[int]NotificationHandler
+	nextHandler          int
+	requestHandlers      map[string]RequestHandler
+	inflight             map[transport.ID]context.CancelFunc
+	roots                []Root
+
+	toolsMu    sync.Mutex
+	tools      map[string]*Tool
+	toolsStale atomic.Bool
+	watchTools sync.Once
+
+	mu              sync.Mutex
+	initialized     bool
+	initResult      map[string]interface{}
+	protocolVersion string
+	serverInfo      Implementation
+	capabilities    ServerCapabilities
+}
+
+// NewSession wraps a transport. Call Initialize before anything else.
+func NewSession(t transport.Transport) *Session {
+	return &Session{
+		transport:            t,
+		notificationHandlers: map[string]map[int]NotificationHandler{},
+		requestHandlers:      map[string]RequestHandler{},
+		inflight:             map[transport.ID]context.CancelFunc{},
+	}
+}
+
+// SetTrace installs a hook that sees every outgoing and incoming message,
+// used for --debug output
+func (s *Session) SetTrace(fn func(direction string, msg interface{})) {
+	s.trace = fn
+}
+
+// SetProtocolVersion pins the protocol version to request. A pinned version
+// must be accepted by the server as-is; without one the client starts at
+// LatestProtocolVersion and negotiates down to what the server supports.
+func (s *Session) SetProtocolVersion(version string) {
+	s.pinnedVersion = version
+}
+
+// Initialize runs the initialize / notifications/initialized handshake.
+// Calling it again returns the result of the first successful handshake.
+func (s *Session) Initialize(ctx context.Context) (map[string]interface{}, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.initialized {
+		return s.initResult, nil
+	}
+
+	requested := s.pinnedVersion
+	if requested == "" {
+		requested = LatestProtocolVersion
+	}
+	if !IsSupportedProtocolVersion(requested) {
+		return nil, &UnsupportedVersionError{Requested: requested}
+	}
+
+	// Listen before the handshake so nothing the server sends early is lost
+	s.startListening()
+
+	result, err := s.initialize(ctx, requested)
+
+	// Servers are supposed to answer with a version they support, but some
+	// reject versions they don't know outright. Unless a version was pinned,
+	// retry with older ones before giving up.
+	var rpcErr *transport.RPCError
+	if errors.As(err, &rpcErr) && s.pinnedVersion == "" {
+		for _, older := range olderProtocolVersions(requested) {
+			if r, retryErr := s.initialize(ctx, older); retryErr == nil {
+				result, err, requested = r, nil, older
+				break
+			}
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	version, _ := result["protocolVersion"].(string)
+	if !IsSupportedProtocolVersion(version) || (s.pinnedVersion != "" && version != s.pinnedVersion) {
+		return nil, &UnsupportedVersionError{Requested: requested, Offered: version}
+	}
+
+	if setter, ok := s.transport.(transport.ProtocolVersionSetter); ok && FeaturesFor(version).ProtocolVersionHeader {
+		setter.SetProtocolVersion(version)
+	}
+
+	// The result is kept as sent for 'init'; what the session needs is typed
+	var typed struct {
+		ServerInfo   Implementation     `json:"serverInfo"`
+		Capabilities ServerCapabilities `json:"capabilities"`
+	}
+	raw, _ := json.Marshal(result)
+	if err := json.Unmarshal(raw, &typed); err != nil {
+		return nil, fmt.Errorf("failed to parse initialize result: %w", err)
+	}
+
+	if err := s.Notify(ctx, "notifications/initialized", nil); err != nil {
+		return nil, fmt.Errorf("failed to send initialized notification: %w", err)
+	}
+
+	s.initialized = true
+	s.initResult = result
+	s.protocolVersion = version
+	s.serverInfo = typed.ServerInfo
+	s.capabilities = typed.Capabilities
+
+	return result, nil
+}
+
+func (s *Session) initialize(ctx context.Context, version string) (map[string]interface{}, error) {
+	params := map[string]interface{}{
+		"protocolVersion": version,
+		"clientInfo": map[string]string{
+			"name":    ClientName,
+			"version": ClientVersion,
+		},
+		"capabilities": s.clientCapabilities(version),
+	}
+
+	var result map[string]interface{}
+	if err := s.call(ctx, "initialize", params, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// InitializeResult returns the server's answer to initialize
+func (s *Session) InitializeResult() map[string]interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.initResult
+}
+
+// ProtocolVersion returns the protocol version the server agreed to
+func (s *Session) ProtocolVersion() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.protocolVersion
+}
+
+// Features returns the version-dependent behavior of the session
+func (s *Session) Features() Features {
+	return FeaturesFor(s.ProtocolVersion())
+}
+
+// ServerInfo returns the server's name and version
+func (s *Session) ServerInfo() Implementation {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.serverInfo
+}
+
+// ServerCapabilities returns the capabilities the server advertised
+func (s *Session) ServerCapabilities() ServerCapabilities {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.capabilities
+}
+
+// HasCapability reports whether the server advertised a top-level
+// capability such as "tools" or "resources"
+func (s *Session) HasCapability(name string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.capabilities.Has(name)
+}
+
+// Call sends a request and decodes its result into out, which may be nil.
+// Errors returned by the server come back as *transport.RPCError.
+func (s *Session) Call(ctx context.Context, method string, params interface{}, out interface{}) error {
+	return s.call(ctx, method, params, out)
+}
+
+func (s *Session) call(ctx context.Context, method string, params interface{}, out interface{}) error {
+	req := transport.RPCRequest{
+		JSONRPC: transport.JSONRPCVersion,
+		ID:      transport.IntID(s.nextID.Add(1)),
+		Method:  method,
+		Params:  params,
+	}
+
+	s.traceMessage("->", req)
+
+	resp, err := s.transport.Send(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	s.traceMessage("<-", resp)
+
+	if resp.Error != nil {
+		return resp.Error
+	}
+
+	if out == nil || len(resp.Result) == 0 {
+		return nil
+	}
+
+	if err := json.Unmarshal(resp.Result, out); err != nil {
+		return fmt.Errorf("failed to parse %s result: %w", method, err)
+	}
+	return nil
+}
+
+// Notify sends a notification to the server
+func (s *Session) Notify(ctx context.Context, method string, params interface{}) error {
+	n := transport.RPCNotification{
+		JSONRPC: transport.JSONRPCVersion,
+		Method:  method,
+		Params:  params,
+	}
+
+	s.traceMessage("->", n)
+	return s.transport.Notify(ctx, n)
+}
+
+func (s *Session) traceMessage(direction string, msg interface{}) {
+	if s.trace != nil {
+		s.trace(direction, msg)
+	}
+}
+
+// Close stops listening and shuts down the underlying transport
+func (s *Session) Close() error {
+	if s.stopListening != nil {
+		s.stopListening()
+	}
+	return s.transport.Close()
+}
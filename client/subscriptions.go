@@ -0,0 +1,45 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// CanSubscribe reports whether the server supports resources/subscribe
+func (s *Session) CanSubscribe() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.capabilities.Resources != nil && s.capabilities.Resources.Subscribe
+}
+
+// Subscribe asks the server to send notifications/resources/updated when
+// the resource at uri changes
+func (s *Session) Subscribe(ctx context.Context, uri string) error {
+	params := map[string]interface{}{
+		"uri": uri,
+	}
+	return s.call(ctx, "resources/subscribe", params, nil)
+}
+
+// Unsubscribe cancels a subscription made with Subscribe
+func (s *Session) Unsubscribe(ctx context.Context, uri string) error {
+	params := map[string]interface{}{
+		"uri": uri,
+	}
+	return s.call(ctx, "resources/unsubscribe", params, nil)
+}
+
+// OnResourceUpdated registers fn for notifications/resources/updated. The
+// returned function removes the handler.
+func (s *Session) OnResourceUpdated(fn func(uri string)) (remove func()) {
+	return s.OnNotification("notifications/resources/updated", func(raw json.RawMessage) {
+		var p struct {
+			URI string `json:"uri"`
+		}
+		if json.Unmarshal(raw, &p) != nil {
+			return
+		}
+		fn(p.URI)
+	})
+}
@@ -0,0 +1,36 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// Tool returns the tool called name as listed by tools/list, or nil if the
+// server doesn't list it. The list is fetched once per session and again
+// after notifications/tools/list_changed.
+func (s *Session) Tool(ctx context.Context, name string) (*Tool, error) {
+	// The handler must not take toolsMu: it runs on the listen loop, which
+	// may be what delivers the tools/list response below
+	s.watchTools.Do(func() {
+		s.OnNotification("notifications/tools/list_changed", func(json.RawMessage) {
+			s.toolsStale.Store(true)
+		})
+	})
+
+	s.toolsMu.Lock()
+	defer s.toolsMu.Unlock()
+
+	if s.tools == nil || s.toolsStale.Swap(false) {
+		listed, err := s.ListAllTools(ctx, PageOptions{})
+		if err != nil {
+			s.toolsStale.Store(s.tools != nil)
+			return nil, err
+		}
+
+		s.tools = map[string]*Tool{}
+		for i := range listed.Items {
+			s.tools[listed.Items[i].Name] = &listed.Items[i]
+		}
+	}
+	return s.tools[name], nil
+}
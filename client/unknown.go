@@ -0,0 +1,131 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// The schema types keep the fields they don't declare in an Unknown map
+// and write them back when encoded, so data from servers speaking a newer
+// protocol version survives a decode/encode round trip, e.g. in --raw
+// output or the tool cache.
+
+// knownFieldNames caches the JSON field names of each type
+var knownFieldNames sync.Map
+
+// decodeKnown decodes data into v, a pointer to a struct type without
+// JSON methods, and stores the fields v doesn't declare in unknown
+func decodeKnown(data []byte, v interface{}, unknown *map[string]json.RawMessage) error {
+	if err := json.Unmarshal(data, v); err != nil {
+		return err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+
+	known := knownFields(reflect.TypeOf(v).Elem())
+	for name := range fields {
+		if known.has(name) {
+			delete(fields, name)
+		}
+	}
+	if len(fields) == 0 {
+		fields = nil
+	}
+	*unknown = fields
+	return nil
+}
+
+// encodeKnown encodes v, a struct without JSON methods, followed by the
+// unknown fields. Declared fields win over unknown ones of the same name.
+func encodeKnown(v interface{}, unknown map[string]json.RawMessage) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil || len(unknown) == 0 {
+		return data, err
+	}
+
+	known := knownFields(reflect.TypeOf(v))
+	names := make([]string, 0, len(unknown))
+	for name := range unknown {
+		if !known.has(name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var buf bytes.Buffer
+	buf.Write(data[:len(data)-1])
+	empty := len(bytes.TrimSpace(data[1:len(data)-1])) == 0
+
+	for _, name := range names {
+		if !empty {
+			buf.WriteByte(',')
+		}
+		empty = false
+
+		key, _ := json.Marshal(name)
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(unknown[name])
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// keepEmpty adds an empty string field called name to data, an object from
+// encodeKnown, for fields the protocol requires that omitempty left out
+func keepEmpty(data []byte, name string) []byte {
+	var buf bytes.Buffer
+	buf.Write(data[:len(data)-1])
+	if len(bytes.TrimSpace(data[1:len(data)-1])) > 0 {
+		buf.WriteByte(',')
+	}
+
+	key, _ := json.Marshal(name)
+	buf.Write(key)
+	buf.WriteString(`:""}`)
+	return buf.Bytes()
+}
+
+// fieldNames are the JSON names of a struct's fields
+type fieldNames []string
+
+// has reports whether encoding/json would decode a key called name into
+// one of the fields, which it matches case-insensitively
+func (f fieldNames) has(name string) bool {
+	for _, field := range f {
+		if strings.EqualFold(field, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// knownFields returns the JSON names of the fields of struct type t
+func knownFields(t reflect.Type) fieldNames {
+	if known, ok := knownFieldNames.Load(t); ok {
+		return known.(fieldNames)
+	}
+
+	var known fieldNames
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		switch name {
+		case "-":
+			continue
+		case "":
+			name = field.Name
+		}
+		known = append(known, name)
+	}
+
+	knownFieldNames.Store(t, known)
+	return known
+}
@@ -0,0 +1,293 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/jkeresman01/mcp-client/client"
+	"github.com/jkeresman01/mcp-client/jsonschema"
+	"github.com/jkeresman01/mcp-client/transport"
+)
+
+// toolArgPairs holds --arg key=value flags
+var toolArgPairs []string
+
+// argSegment is one step of an --arg key: a property name, an index, or
+// [] to append
+type argSegment struct {
+	name   string
+	index  int
+	isItem bool
+	append bool
+}
+
+// buildToolArgs merges key=value pairs into base, the parsed --args. Keys
+// are paths like filter.since, tags[] or items[0].name; values are
+// converted to the type the tool's inputSchema declares at that path, and
+// @file reads a value from a file.
+func buildToolArgs(ctx context.Context, s *client.Session, name string, base map[string]interface{}, pairs []string) (map[string]interface{}, error) {
+	if len(pairs) == 0 {
+		return base, nil
+	}
+
+	schema, root := toolSchema(ctx, s, name)
+
+	args := base
+	if args == nil {
+		args = map[string]interface{}{}
+	}
+
+	for _, pair := range pairs {
+		key, raw, ok := strings.Cut(pair, "=")
+		if !ok || key == "" {
+			return nil, argError(pair, fmt.Errorf("expected key=value"))
+		}
+
+		segments, err := parseArgKey(key)
+		if err != nil {
+			return nil, argError(pair, err)
+		}
+
+		value, err := argValue(raw)
+		if err != nil {
+			return nil, argError(pair, err)
+		}
+
+		updated, err := assignArg(schema, args, root, segments, value)
+		if err != nil {
+			return nil, argError(pair, err)
+		}
+		args = updated.(map[string]interface{})
+	}
+	return args, nil
+}
+
+// toolSchema compiles the tool's inputSchema for --arg conversions. Without
+// one every value stays a string.
+func toolSchema(ctx context.Context, s *client.Session, name string) (*jsonschema.Schema, interface{}) {
+	tool, err := s.Tool(ctx, name)
+	if err != nil || tool == nil {
+		return nil, nil
+	}
+
+	schema, err := jsonschema.Compile(tool.InputSchema)
+	if err != nil {
+		return nil, nil
+	}
+	return schema, schema.Root()
+}
+
+func argError(pair string, err error) error {
+	return &transport.MCPError{
+		Operation: "parsing --arg",
+		Err:       fmt.Errorf("invalid --arg %q: %v", pair, err),
+		Hints: []string{
+			"Write arguments as key=value, e.g. --arg query=golang --arg limit=10",
+			"Nest with dots and append to lists with []: --arg filter.since=2024-01-01 --arg tags[]=a",
+			"Read a value from a file: --arg body=@message.txt",
+		},
+	}
+}
+
+// parseArgKey splits a key like items[0].name into its segments
+func parseArgKey(key string) ([]argSegment, error) {
+	var segments []argSegment
+
+	for _, part := range strings.Split(key, ".") {
+		name, rest, _ := strings.Cut(part, "[")
+		if name == "" && (len(segments) == 0 || rest == "") {
+			return nil, fmt.Errorf("empty name in key %q", key)
+		}
+		if name != "" {
+			segments = append(segments, argSegment{name: name})
+		}
+
+		for rest != "" {
+			inside, after, ok := strings.Cut(rest, "]")
+			if !ok {
+				return nil, fmt.Errorf("missing ']' in key %q", key)
+			}
+
+			if inside == "" {
+				segments = append(segments, argSegment{isItem: true, append: true})
+			} else {
+				i, err := strconv.Atoi(inside)
+				if err != nil || i < 0 {
+					return nil, fmt.Errorf("invalid index %q in key %q", inside, key)
+				}
+				segments = append(segments, argSegment{isItem: true, index: i})
+			}
+
+			if after != "" && !strings.HasPrefix(after, "[") {
+				return nil, fmt.Errorf("unexpected %q in key %q", after, key)
+			}
+			rest = strings.TrimPrefix(after, "[")
+		}
+	}
+	return segments, nil
+}
+
+// argValue reads @file values; @@ escapes a literal leading @
+func argValue(raw string) (string, error) {
+	if strings.HasPrefix(raw, "@@") {
+		return raw[1:], nil
+	}
+	if !strings.HasPrefix(raw, "@") {
+		return raw, nil
+	}
+
+	data, err := os.ReadFile(raw[1:])
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// assignArg sets value at segments inside container, creating objects and
+// lists on the way, and returns the updated container
+func assignArg(schema *jsonschema.Schema, container, node interface{}, segments []argSegment, value string) (interface{}, error) {
+	if len(segments) == 0 {
+		return coerceArg(schema, node, value)
+	}
+	seg := segments[0]
+
+	if !seg.isItem {
+		m, ok := container.(map[string]interface{})
+		if container != nil && !ok {
+			return nil, fmt.Errorf("%q is set on a value that isn't an object", seg.name)
+		}
+		if m == nil {
+			m = map[string]interface{}{}
+		}
+
+		var sub interface{}
+		if schema != nil {
+			sub = schema.Property(node, seg.name)
+		}
+		v, err := assignArg(schema, m[seg.name], sub, segments[1:], value)
+		if err != nil {
+			return nil, err
+		}
+		m[seg.name] = v
+		return m, nil
+	}
+
+	list, ok := container.([]interface{})
+	if container != nil && !ok {
+		return nil, fmt.Errorf("an item is added to a value that isn't a list")
+	}
+
+	// Items are set in order, so a typo like items[99999999] can't make a
+	// huge list of nulls
+	i := seg.index
+	if seg.append {
+		i = len(list)
+	}
+	if i > len(list) {
+		return nil, fmt.Errorf("index %d skips items, the next one is %d", i, len(list))
+	}
+	if i == len(list) {
+		list = append(list, nil)
+	}
+
+	var sub interface{}
+	if schema != nil {
+		sub = schema.Item(node, i)
+	}
+	v, err := assignArg(schema, list[i], sub, segments[1:], value)
+	if err != nil {
+		return nil, err
+	}
+	list[i] = v
+	return list, nil
+}
+
+// coerceArg converts a string to the first type node allows that it
+// parses as. Strings are tried last, so "10" for ["integer", "string"] is
+// the number 10. Without a schema the value stays a string.
+func coerceArg(schema *jsonschema.Schema, node interface{}, value string) (interface{}, error) {
+	var types []string
+	if schema != nil && node != nil {
+		types = schema.Types(node)
+	}
+	if len(types) == 0 {
+		return value, nil
+	}
+
+	trimmed := strings.TrimSpace(value)
+	allowsString := false
+	nonFinite := false
+
+	for _, t := range types {
+		switch t {
+		case "string":
+			allowsString = true
+
+		case "integer":
+			if n, err := strconv.ParseInt(trimmed, 10, 64); err == nil {
+				return n, nil
+			}
+
+		case "number":
+			if f, err := strconv.ParseFloat(trimmed, 64); err == nil {
+				// JSON has no NaN or Infinity
+				if math.IsNaN(f) || math.IsInf(f, 0) {
+					nonFinite = true
+					continue
+				}
+				return f, nil
+			}
+
+		case "boolean":
+			switch strings.ToLower(trimmed) {
+			case "true", "yes", "y", "on", "1":
+				return true, nil
+			case "false", "no", "n", "off", "0":
+				return false, nil
+			}
+
+		case "null":
+			if trimmed == "null" || trimmed == "" {
+				return nil, nil
+			}
+
+		case "array":
+			if strings.HasPrefix(trimmed, "[") {
+				var list []interface{}
+				if err := json.Unmarshal([]byte(trimmed), &list); err == nil {
+					return list, nil
+				}
+				continue
+			}
+			// a,b,c is a list, each item converted to the items' type
+			var list []interface{}
+			for i, item := range strings.Split(value, ",") {
+				v, err := coerceArg(schema, schema.Item(node, i), item)
+				if err != nil {
+					return nil, err
+				}
+				list = append(list, v)
+			}
+			return list, nil
+
+		case "object":
+			var obj map[string]interface{}
+			if err := json.Unmarshal([]byte(trimmed), &obj); err == nil {
+				return obj, nil
+			}
+		}
+	}
+
+	if allowsString {
+		return value, nil
+	}
+	if nonFinite {
+		return nil, fmt.Errorf("%q is not a valid %s: JSON numbers must be finite", value, strings.Join(types, " or "))
+	}
+	return nil, fmt.Errorf("%q is not a valid %s", value, strings.Join(types, " or "))
+}
@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"sync"
+)
+
+// errNoPrompt is returned by askUser when there is nobody to ask
+var errNoPrompt = errors.New("not running interactively")
+
+var (
+	// interactiveMode is set while the REPL runs
+	interactiveMode bool
+
+	// userInput hands stdin to the REPL prompt and to questions from
+	// server requests in turn
+	userInput = newInputTurns()
+)
+
+// inputTurns lets one reader at a time have stdin. Questions take it over
+// from the REPL prompt, which gives way and comes back once no question is
+// left, so a server request doesn't wait for the next command.
+type inputTurns struct {
+	mu sync.Mutex
+
+	// turn holds a token while nobody has stdin
+	turn chan struct{}
+
+	// waiting counts the questions waiting for or holding stdin; idle is
+	// closed when it is zero
+	waiting int
+	idle    chan struct{}
+
+	// interrupt stops the REPL prompt while it has stdin
+	interrupt context.CancelFunc
+}
+
+func newInputTurns() *inputTurns {
+	t := &inputTurns{
+		turn: make(chan struct{}, 1),
+		idle: make(chan struct{}),
+	}
+	t.turn <- struct{}{}
+	close(t.idle)
+	return t
+}
+
+// ask waits until stdin is free for a question, interrupting the REPL
+// prompt, or until ctx is done. A nil error must be followed by done.
+func (t *inputTurns) ask(ctx context.Context) error {
+	t.mu.Lock()
+	if t.waiting == 0 {
+		t.idle = make(chan struct{})
+	}
+	t.waiting++
+	if t.interrupt != nil {
+		t.interrupt()
+	}
+	t.mu.Unlock()
+
+	select {
+	case <-t.turn:
+		return nil
+	case <-ctx.Done():
+		t.leave()
+		return ctx.Err()
+	}
+}
+
+// done gives stdin back after a question
+func (t *inputTurns) done() {
+	t.leave()
+	t.turn <- struct{}{}
+}
+
+func (t *inputTurns) leave() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.waiting--
+	if t.waiting == 0 {
+		close(t.idle)
+	}
+}
+
+// prompt waits until no question wants stdin and takes it for the REPL
+// prompt. The context is cancelled when a question comes in; release gives
+// stdin back.
+func (t *inputTurns) prompt() (ctx context.Context, release func()) {
+	for {
+		t.mu.Lock()
+		idle := t.idle
+		t.mu.Unlock()
+
+		<-idle
+		<-t.turn
+
+		t.mu.Lock()
+		if t.waiting == 0 {
+			ctx, cancel := context.WithCancel(context.Background())
+			t.interrupt = cancel
+			t.mu.Unlock()
+
+			return ctx, func() {
+				t.mu.Lock()
+				t.interrupt = nil
+				t.mu.Unlock()
+
+				cancel()
+				t.turn <- struct{}{}
+			}
+		}
+		t.mu.Unlock()
+
+		// A question came in meanwhile
+		t.turn <- struct{}{}
+	}
+}
+
+// canAsk reports whether there is a user to answer questions
+func canAsk() bool {
+	return interactiveMode || isTerminal(os.Stdin)
+}
+
+// askUser reads one line of input from the user, giving up when ctx is
+// done. Callers have their turn from userInput.ask.
+func askUser(ctx context.Context, prompt string) (string, error) {
+	if !canAsk() {
+		return "", errNoPrompt
+	}
+	return newLineReader(nil).ReadLine(ctx, prompt)
+}
+
+// confirm asks a yes/no question; anything but yes means no
+func confirm(ctx context.Context, question string) bool {
+	answer, err := askUser(ctx, question+" [y/N] ")
+	if err != nil {
+		return false
+	}
+
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
@@ -0,0 +1,392 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/jkeresman01/mcp-client/client"
+	"github.com/jkeresman01/mcp-client/jsonschema"
+)
+
+// errArgsCancelled is returned when the user gives up on a form
+var errArgsCancelled = errors.New("cancelled")
+
+// maxAskDepth stops asking for nested objects field by field, e.g. in a
+// recursive schema; deeper values are typed as JSON
+const maxAskDepth = 8
+
+// askToolArgs walks the user through the properties of a tool's
+// inputSchema. It returns nil arguments if the tool or its schema is
+// unknown, leaving the server to complain.
+func askToolArgs(ctx context.Context, s *client.Session, name string) (map[string]interface{}, error) {
+	tool, err := s.Tool(ctx, name)
+	if err != nil || tool == nil {
+		return nil, nil
+	}
+
+	schema, err := jsonschema.Compile(tool.InputSchema)
+	if err != nil {
+		fmt.Printf("Warning: can't use the inputSchema of tool '%s': %v\n", name, err)
+		return nil, nil
+	}
+	schema.AssertFormats = true
+
+	root := schema.Root()
+	if len(schema.Properties(root)) == 0 {
+		return nil, nil
+	}
+
+	if err := userInput.ask(context.Background()); err != nil {
+		return nil, err
+	}
+	defer userInput.done()
+
+	fmt.Printf("Arguments for %s (* marks required, empty skips, Ctrl-C cancels)\n", name)
+	args, err := askObject(schema, root, "$", "  ", 0)
+	if err != nil {
+		return nil, err
+	}
+
+	output, _ := json.MarshalIndent(args, "", "  ")
+	fmt.Printf("Arguments:\n%s\n", string(output))
+	return args, nil
+}
+
+// askObject asks for each property of node, required ones first
+func askObject(schema *jsonschema.Schema, node interface{}, path, indent string, depth int) (map[string]interface{}, error) {
+	required := map[string]bool{}
+	for _, name := range schema.Required(node) {
+		required[name] = true
+	}
+
+	var names []string
+	for _, name := range schema.Properties(node) {
+		if required[name] {
+			names = append(names, name)
+		}
+	}
+	for _, name := range schema.Properties(node) {
+		if !required[name] {
+			names = append(names, name)
+		}
+	}
+
+	obj := map[string]interface{}{}
+	for _, name := range names {
+		v, ok, err := askValue(schema, schema.Property(node, name), path+"."+name, name, required[name], indent, depth)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			obj[name] = v
+		}
+	}
+	return obj, nil
+}
+
+// askValue asks for one value, as a nested form for objects and lists.
+// It reports false if the user skipped an optional value.
+func askValue(schema *jsonschema.Schema, node interface{}, path, label string, required bool, indent string, depth int) (interface{}, bool, error) {
+	types := schema.Types(node)
+	single := ""
+	if len(types) == 1 {
+		single = types[0]
+	}
+
+	if description, ok := schema.Keyword(node, "description"); ok {
+		fmt.Printf("%s%v\n", indent, description)
+	}
+
+	switch {
+	case single == "object" && len(schema.Properties(node)) > 0 && depth < maxAskDepth:
+		if !required {
+			if yes, err := askYesNo(indent + "Set " + label + "?"); err != nil || !yes {
+				return nil, false, err
+			}
+		}
+		// Start over if the fields don't go together, e.g. for dependentRequired
+		for {
+			fmt.Printf("%s%s:\n", indent, label)
+			obj, err := askObject(schema, node, path, indent+"  ", depth+1)
+			if err != nil {
+				return nil, false, err
+			}
+			if checkAnswer(schema, node, obj, path, indent) == nil {
+				return obj, true, nil
+			}
+		}
+
+	case single == "array" && depth < maxAskDepth:
+		return askList(schema, node, path, label, required, indent, depth)
+	}
+
+	return askScalar(schema, node, path, label, required, false, indent)
+}
+
+// askList asks for items until an empty answer, then checks the whole list
+// (uniqueItems, minItems, ...) and starts over if it doesn't pass
+func askList(schema *jsonschema.Schema, node interface{}, path, label string, required bool, indent string, depth int) (interface{}, bool, error) {
+	for {
+		fmt.Printf("%s%s: one item per answer, empty to finish\n", indent, labelMark(label, required))
+
+		list := []interface{}{}
+		for i := 0; ; i++ {
+			item := schema.Item(node, i)
+			itemLabel := fmt.Sprintf("%s[%d]", label, i)
+			itemPath := fmt.Sprintf("%s[%d]", path, i)
+
+			var v interface{}
+			var ok bool
+			var err error
+			if types := schema.Types(item); len(types) == 1 && types[0] == "object" && len(schema.Properties(item)) > 0 {
+				if ok, err = askYesNo(indent + "  Add " + itemLabel + "?"); ok {
+					v, ok, err = askValue(schema, item, itemPath, itemLabel, true, indent+"  ", depth+1)
+				}
+			} else {
+				v, ok, err = askScalar(schema, item, itemPath, itemLabel, false, true, indent+"  ")
+			}
+			if err != nil {
+				return nil, false, err
+			}
+			if !ok {
+				break
+			}
+			list = append(list, v)
+		}
+
+		if len(list) == 0 && !required {
+			return nil, false, nil
+		}
+		if checkAnswer(schema, node, list, path, indent) == nil {
+			return list, true, nil
+		}
+	}
+}
+
+// askScalar asks for a single value until it converts to the schema's
+// type and passes its constraints. An empty answer takes the default, if
+// any, or skips an optional value. For a list item it always ends the
+// list, whatever the items' default.
+func askScalar(schema *jsonschema.Schema, node interface{}, path, label string, required, item bool, indent string) (interface{}, bool, error) {
+	choices := schema.Enum(node)
+	for i, c := range choices {
+		fmt.Printf("%s  %d) %s\n", indent, i+1, describeChoice(c))
+	}
+	def, hasDefault := schema.Keyword(node, "default")
+	hasDefault = hasDefault && !item
+
+	for {
+		input, err := askUser(context.Background(), indent+scalarPrompt(schema, node, label, required, hasDefault))
+		if err != nil {
+			return nil, false, errArgsCancelled
+		}
+
+		input = strings.TrimSpace(input)
+		if input == "" {
+			if hasDefault {
+				return def, true, nil
+			}
+			if required {
+				fmt.Printf("%s  This field is required\n", indent)
+				continue
+			}
+			return nil, false, nil
+		}
+
+		v, err := parseAnswer(schema, node, choices, input)
+		if err != nil {
+			fmt.Printf("%s  %v\n", indent, err)
+			continue
+		}
+		if checkAnswer(schema, node, v, path, indent) != nil {
+			continue
+		}
+		return v, true, nil
+	}
+}
+
+// parseAnswer converts typed input like --arg does. A number picks a
+// listed choice unless it is a choice itself; without a type JSON is
+// accepted too.
+func parseAnswer(schema *jsonschema.Schema, node interface{}, choices []interface{}, input string) (interface{}, error) {
+	if i, err := strconv.Atoi(input); err == nil && i >= 1 && i <= len(choices) {
+		picked := true
+		for _, c := range choices {
+			if fmt.Sprint(c) == input {
+				picked = false
+			}
+		}
+		if picked {
+			return choices[i-1], nil
+		}
+	}
+
+	if len(schema.Types(node)) == 0 {
+		var v interface{}
+		if err := json.Unmarshal([]byte(input), &v); err == nil {
+			return v, nil
+		}
+		return input, nil
+	}
+	return coerceArg(schema, node, input)
+}
+
+// checkAnswer validates a value against its subschema and prints what's
+// wrong with it
+func checkAnswer(schema *jsonschema.Schema, node, value interface{}, path, indent string) error {
+	err := schema.ValidateAt(node, value, path)
+
+	var verr *jsonschema.ValidationError
+	if errors.As(err, &verr) {
+		for _, e := range verr.Errors {
+			if e.Path == path {
+				fmt.Printf("%s  %s\n", indent, e.Message)
+			} else {
+				fmt.Printf("%s  %s\n", indent, e.String())
+			}
+		}
+	}
+	return err
+}
+
+// scalarPrompt renders the question for a value, e.g.
+// "limit (integer, 1-100) [10]: ", with the default if showDefault is set
+func scalarPrompt(schema *jsonschema.Schema, node interface{}, label string, required, showDefault bool) string {
+	var hints []string
+
+	types := schema.Types(node)
+	switch {
+	case len(schema.Enum(node)) > 0:
+		hints = append(hints, "choose 1-"+strconv.Itoa(len(schema.Enum(node))))
+	case len(types) == 1 && types[0] == "boolean":
+		hints = append(hints, "y/n")
+	case len(types) == 1 && types[0] == "array":
+		hints = append(hints, "comma-separated or JSON")
+	case len(types) > 0:
+		hints = append(hints, strings.Join(types, " or "))
+	}
+	if format, ok := schema.Keyword(node, "format"); ok {
+		hints = append(hints, fmt.Sprint(format))
+	}
+	if r := schemaRange(schema, node, types); r != "" {
+		hints = append(hints, r)
+	}
+
+	prompt := labelMark(label, required)
+	if len(hints) > 0 {
+		prompt += " (" + strings.Join(hints, ", ") + ")"
+	}
+	if def, ok := schema.Keyword(node, "default"); ok && showDefault {
+		out, _ := json.Marshal(def)
+		prompt += " [" + string(out) + "]"
+	}
+	return prompt + ": "
+}
+
+// schemaRange describes minimum/maximum, or the length limits of strings
+func schemaRange(schema *jsonschema.Schema, node interface{}, types []string) string {
+	loKey, hiKey, unit := "minimum", "maximum", ""
+	if len(types) == 1 && types[0] == "string" {
+		loKey, hiKey, unit = "minLength", "maxLength", " chars"
+	}
+
+	lo, hasLo := schema.Keyword(node, loKey)
+	hi, hasHi := schema.Keyword(node, hiKey)
+	switch {
+	case hasLo && hasHi:
+		return fmt.Sprintf("%v-%v%s", lo, hi, unit)
+	case hasLo:
+		return fmt.Sprintf(">= %v%s", lo, unit)
+	case hasHi:
+		return fmt.Sprintf("<= %v%s", hi, unit)
+	}
+	return ""
+}
+
+func labelMark(label string, required bool) string {
+	if required {
+		return label + "*"
+	}
+	return label
+}
+
+func describeChoice(v interface{}) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	out, _ := json.Marshal(v)
+	return string(out)
+}
+
+// askYesNo asks a yes/no question; an empty answer is no
+func askYesNo(question string) (bool, error) {
+	for {
+		answer, err := askUser(context.Background(), question+" [y/N] ")
+		if err != nil {
+			return false, errArgsCancelled
+		}
+
+		switch strings.ToLower(strings.TrimSpace(answer)) {
+		case "y", "yes":
+			return true, nil
+		case "", "n", "no":
+			return false, nil
+		}
+	}
+}
+
+// askPromptArgs asks for each argument a prompt declares, required ones
+// marked. It returns nil if the prompt is unknown, leaving the server to
+// complain.
+func askPromptArgs(ctx context.Context, s *client.Session, name string) (map[string]interface{}, error) {
+	result, err := s.ListAllPrompts(ctx, client.PageOptions{})
+	if err != nil {
+		return nil, nil
+	}
+
+	var arguments []client.PromptArgument
+	for _, p := range result.Items {
+		if p.Name == name {
+			arguments = p.Arguments
+		}
+	}
+	if len(arguments) == 0 {
+		return nil, nil
+	}
+
+	if err := userInput.ask(context.Background()); err != nil {
+		return nil, err
+	}
+	defer userInput.done()
+
+	fmt.Printf("Arguments for %s (* marks required, empty skips, Ctrl-C cancels)\n", name)
+
+	args := map[string]interface{}{}
+	for _, arg := range arguments {
+		if arg.Name == "" {
+			continue
+		}
+		if arg.Description != "" {
+			fmt.Printf("  %s\n", arg.Description)
+		}
+
+		for {
+			input, err := askUser(context.Background(), "  "+labelMark(arg.Name, arg.Required)+": ")
+			if err != nil {
+				return nil, errArgsCancelled
+			}
+			if input = strings.TrimSpace(input); input != "" {
+				args[arg.Name] = input
+			} else if arg.Required {
+				fmt.Println("    This argument is required")
+				continue
+			}
+			break
+		}
+	}
+	return args, nil
+}
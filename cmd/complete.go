@@ -0,0 +1,428 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jkeresman01/mcp-client/client"
+	"github.com/jkeresman01/mcp-client/jsonschema"
+	"github.com/jkeresman01/mcp-client/uritemplate"
+	"github.com/spf13/cobra"
+)
+
+// completionTimeout bounds connecting and the requests made while
+// completing, so a slow server can't hang the shell
+const completionTimeout = 2 * time.Second
+
+// completionContext returns the context completion requests run under
+func completionContext() (context.Context, context.CancelFunc) {
+	timeout := completionTimeout
+	if requestTimeout > 0 && requestTimeout < timeout {
+		timeout = requestTimeout
+	}
+	return context.WithTimeout(context.Background(), timeout)
+}
+
+// completeToolNames returns the names of the server's tools starting with
+// prefix
+func completeToolNames(ctx context.Context, s *client.Session, prefix string) []string {
+	result, err := s.ListAllTools(ctx, client.PageOptions{})
+	if err != nil {
+		return nil
+	}
+	return itemFields(result.Items, func(t client.Tool) string { return t.Name }, prefix)
+}
+
+// completePromptNames returns the names of the server's prompts starting
+// with prefix
+func completePromptNames(ctx context.Context, s *client.Session, prefix string) []string {
+	result, err := s.ListAllPrompts(ctx, client.PageOptions{})
+	if err != nil {
+		return nil
+	}
+	return itemFields(result.Items, func(p client.Prompt) string { return p.Name }, prefix)
+}
+
+// completeResourceURIs returns resource URIs starting with prefix, and the
+// URI templates as well when templates is set
+func completeResourceURIs(ctx context.Context, s *client.Session, prefix string, templates bool) []string {
+	var uris []string
+
+	if result, err := s.ListAllResources(ctx, client.PageOptions{}); err == nil {
+		uris = append(uris, itemFields(result.Items, func(r client.Resource) string { return r.URI }, prefix)...)
+	}
+	if templates {
+		uris = append(uris, completeTemplateURIs(ctx, s, prefix)...)
+	}
+	return uris
+}
+
+// completeTemplateURIs returns the server's URI templates starting with
+// prefix
+func completeTemplateURIs(ctx context.Context, s *client.Session, prefix string) []string {
+	result, err := s.ListAllResourceTemplates(ctx, client.PageOptions{})
+	if err != nil {
+		return nil
+	}
+	return itemFields(result.Items, func(t client.ResourceTemplate) string { return t.URITemplate }, prefix)
+}
+
+// completePromptArgument completes a key=value token for a prompt's
+// arguments. entered holds the arguments already given.
+func completePromptArgument(ctx context.Context, s *client.Session, prompt string, entered map[string]string, token string) []string {
+	var names []string
+
+	if result, err := s.ListAllPrompts(ctx, client.PageOptions{}); err == nil {
+		for _, p := range result.Items {
+			if p.Name == prompt {
+				names = itemFields(p.Arguments, func(a client.PromptArgument) string { return a.Name }, "")
+			}
+		}
+	}
+
+	return completePair(ctx, s, client.PromptRef(prompt), names, entered, token)
+}
+
+// completeTemplateVariable completes a key=value token for the variables of
+// a resource template
+func completeTemplateVariable(ctx context.Context, s *client.Session, template string, entered map[string]string, token string) []string {
+	tpl, err := uritemplate.Parse(template)
+	if err != nil {
+		return nil
+	}
+	return completePair(ctx, s, client.ResourceTemplateRef(template), tpl.Varnames(), entered, token)
+}
+
+// completeToolArgument completes a key=value token for --arg from the
+// tool's inputSchema: property names, with "." after objects and "[]="
+// after lists, and enum or boolean values once the key is complete
+func completeToolArgument(ctx context.Context, s *client.Session, tool string, entered map[string]string, token string) []string {
+	schema, root := toolSchema(ctx, s, tool)
+	if schema == nil {
+		return nil
+	}
+
+	key, value, hasValue := strings.Cut(token, "=")
+	if hasValue {
+		node, ok := argSchemaAt(schema, root, key)
+		if !ok {
+			return nil
+		}
+
+		var candidates []string
+		for _, v := range schema.Enum(node) {
+			if text := fmt.Sprint(v); strings.HasPrefix(text, value) {
+				candidates = append(candidates, key+"="+text)
+			}
+		}
+		if len(candidates) == 0 && slices.Contains(schema.Types(node), "boolean") {
+			candidates = filterPrefix([]string{key + "=true", key + "=false"}, token)
+		}
+		return candidates
+	}
+
+	parent := ""
+	node := root
+	if i := strings.LastIndex(key, "."); i >= 0 {
+		parent = key[:i+1]
+		var ok bool
+		if node, ok = argSchemaAt(schema, root, key[:i]); !ok {
+			return nil
+		}
+	}
+
+	var candidates []string
+	for _, name := range schema.Properties(node) {
+		full := parent + name
+		if _, done := entered[full]; done || !strings.HasPrefix(full, key) {
+			continue
+		}
+
+		types := schema.Types(schema.Property(node, name))
+		switch {
+		case slices.Contains(types, "object"):
+			candidates = append(candidates, full+".")
+		case slices.Contains(types, "array"):
+			candidates = append(candidates, full+"[]=")
+		default:
+			candidates = append(candidates, full+"=")
+		}
+	}
+	return candidates
+}
+
+// argSchemaAt finds the subschema an --arg key refers to
+func argSchemaAt(schema *jsonschema.Schema, root interface{}, key string) (interface{}, bool) {
+	segments, err := parseArgKey(key)
+	if err != nil {
+		return nil, false
+	}
+
+	node := root
+	for _, seg := range segments {
+		if seg.isItem {
+			node = schema.Item(node, seg.index)
+		} else {
+			node = schema.Property(node, seg.name)
+		}
+		if node == nil {
+			return nil, false
+		}
+	}
+	return node, true
+}
+
+// completePair suggests "name=" for argument names that were not entered
+// yet, and "name=value" from completion/complete once the name is complete
+func completePair(ctx context.Context, s *client.Session, ref client.CompletionRef, names []string, entered map[string]string, token string) []string {
+	name, value, hasValue := strings.Cut(token, "=")
+
+	if !hasValue {
+		var candidates []string
+		for _, n := range names {
+			if _, done := entered[n]; !done && strings.HasPrefix(n, name) {
+				candidates = append(candidates, n+"=")
+			}
+		}
+		return candidates
+	}
+
+	if !s.CanComplete() {
+		return nil
+	}
+
+	completion, err := s.Complete(ctx, ref, name, value, entered)
+	if err != nil {
+		return nil
+	}
+
+	candidates := make([]string, 0, len(completion.Values))
+	for _, v := range completion.Values {
+		candidates = append(candidates, name+"="+v)
+	}
+	return candidates
+}
+
+// itemFields collects a string field of list items, keeping those that start
+// with prefix
+func itemFields[T any](items []T, field func(T) string, prefix string) []string {
+	var values []string
+	for _, item := range items {
+		if v := field(item); v != "" && strings.HasPrefix(v, prefix) {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// filterPrefix keeps the values that start with prefix
+func filterPrefix(values []string, prefix string) []string {
+	var matches []string
+	for _, v := range values {
+		if strings.HasPrefix(v, prefix) {
+			matches = append(matches, v)
+		}
+	}
+	return matches
+}
+
+// enteredPairs collects the key=value pairs typed so far, ignoring anything
+// that isn't one
+func enteredPairs(pairs []string) map[string]string {
+	entered := map[string]string{}
+	for _, pair := range pairs {
+		if key, value, ok := strings.Cut(pair, "="); ok && key != "" {
+			entered[key] = value
+		}
+	}
+	return entered
+}
+
+// shellCompletion wraps a completion source for cobra. It connects to the
+// configured server quietly, since the shell reads completions from stdout.
+func shellCompletion(fn func(ctx context.Context, s *client.Session, toComplete string) ([]string, cobra.ShellCompDirective)) cobra.CompletionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+		debugMode = false
+		serverLogLevel = ""
+		serverLogFile = ""
+
+		if err := applyServerConfig(cmd); err != nil {
+			return nil, cobra.ShellCompDirectiveError
+		}
+
+		ctx, cancel := completionContext()
+		defer cancel()
+
+		s, err := connectForCompletion(ctx)
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveError
+		}
+		defer s.Close()
+
+		return fn(ctx, s, toComplete)
+	}
+}
+
+// pairDirective keeps the cursor after "name=" (or "name." for nested
+// keys) so a value can follow
+func pairDirective(candidates []string) cobra.ShellCompDirective {
+	for _, c := range candidates {
+		if strings.HasSuffix(c, "=") || strings.HasSuffix(c, ".") {
+			return cobra.ShellCompDirectiveNoSpace | cobra.ShellCompDirectiveNoFileComp
+		}
+	}
+	return cobra.ShellCompDirectiveNoFileComp
+}
+
+// Flag completions, registered by the commands that own the flags
+var (
+	toolNameCompletion = shellCompletion(
+		func(ctx context.Context, s *client.Session, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return completeToolNames(ctx, s, toComplete), cobra.ShellCompDirectiveNoFileComp
+		})
+
+	// Only needed while nothing is cached; the cached tools are subcommands
+	toolCommandCompletion = shellCompletion(
+		func(ctx context.Context, s *client.Session, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if toolCmd.HasSubCommands() {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+			result, err := s.ListAllTools(ctx, client.PageOptions{})
+			if err != nil {
+				return nil, cobra.ShellCompDirectiveError
+			}
+			cache := saveToolCache(s, result.Items)
+
+			names := itemFields(cache.Tools, func(t client.Tool) string { return t.Name }, toComplete)
+			return names, cobra.ShellCompDirectiveNoFileComp
+		})
+
+	toolArgCompletion = shellCompletion(
+		func(ctx context.Context, s *client.Session, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if toolName == "" {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+			candidates := completeToolArgument(ctx, s, toolName, enteredPairs(toolArgPairs), toComplete)
+			return candidates, pairDirective(candidates)
+		})
+
+	promptNameCompletion = shellCompletion(
+		func(ctx context.Context, s *client.Session, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return completePromptNames(ctx, s, toComplete), cobra.ShellCompDirectiveNoFileComp
+		})
+
+	// --arguments is completed in its key=value,key=value form: everything
+	// up to the last comma is kept and the last pair is completed
+	promptArgumentsCompletion = shellCompletion(
+		func(ctx context.Context, s *client.Session, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if promptName == "" || strings.HasPrefix(toComplete, "{") {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+
+			done, token := "", toComplete
+			if i := strings.LastIndex(toComplete, ","); i >= 0 {
+				done, token = toComplete[:i+1], toComplete[i+1:]
+			}
+
+			entered := enteredPairs(strings.Split(done, ","))
+			candidates := completePromptArgument(ctx, s, promptName, entered, token)
+			for i, c := range candidates {
+				candidates[i] = done + c
+			}
+			return candidates, cobra.ShellCompDirectiveNoSpace | cobra.ShellCompDirectiveNoFileComp
+		})
+
+	resourceURICompletion = shellCompletion(
+		func(ctx context.Context, s *client.Session, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return completeResourceURIs(ctx, s, toComplete, false), cobra.ShellCompDirectiveNoFileComp
+		})
+
+	templateURICompletion = shellCompletion(
+		func(ctx context.Context, s *client.Session, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return completeTemplateURIs(ctx, s, toComplete), cobra.ShellCompDirectiveNoFileComp
+		})
+
+	templateVarCompletion = shellCompletion(
+		func(ctx context.Context, s *client.Session, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if resourceTemplate == "" {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+			candidates := completeTemplateVariable(ctx, s, resourceTemplate, enteredPairs(templateVars), toComplete)
+			return candidates, pairDirective(candidates)
+		})
+)
+
+// interactiveCommands are offered when completing the first word in the REPL
+var interactiveCommands = []string{
+	"help", "list-tools", "list-resources", "list-resource-templates", "list-prompts",
+	"call", "get-resource", "get-prompt", "watch", "roots", "exit",
+}
+
+// replCompleter completes REPL input from the same sources as the shell
+// completions
+func replCompleter(s *client.Session) completer {
+	return func(line string) ([]string, int) {
+		start := strings.LastIndex(line, " ") + 1
+		word := line[start:]
+		words := parseCommandLine(line[:start])
+
+		if len(words) == 0 {
+			return filterPrefix(interactiveCommands, word), start
+		}
+
+		ctx, cancel := completionContext()
+		defer cancel()
+
+		switch strings.ToLower(words[0]) {
+		case "call", "c":
+			if len(words) == 1 {
+				return completeToolNames(ctx, s, word), start
+			}
+			if len(words) == 2 || !strings.HasPrefix(words[2], "{") {
+				return completeToolArgument(ctx, s, words[1], enteredPairs(words[2:]), word), start
+			}
+
+		case "get-prompt", "gp":
+			if len(words) == 1 {
+				return completePromptNames(ctx, s, word), start
+			}
+			return completePromptArgument(ctx, s, words[1], enteredPairs(words[2:]), word), start
+
+		case "get-resource", "gr":
+			if len(words) == 1 {
+				return completeResourceURIs(ctx, s, word, true), start
+			}
+			if strings.Contains(words[1], "{") {
+				return completeTemplateVariable(ctx, s, words[1], enteredPairs(words[2:]), word), start
+			}
+
+		case "watch", "w":
+			if len(words) == 1 {
+				return completeResourceURIs(ctx, s, word, false), start
+			}
+			if strings.HasPrefix("--diff", word) {
+				return []string{"--diff"}, start
+			}
+
+		case "roots":
+			if len(words) == 1 {
+				return filterPrefix([]string{"list", "add", "remove"}, word), start
+			}
+			if len(words) == 2 && words[1] == "remove" {
+				var uris []string
+				for _, r := range s.Roots() {
+					uris = append(uris, r.URI)
+				}
+				return filterPrefix(uris, word), start
+			}
+
+		case "list-tools", "lt", "list-resources", "lr", "list-resource-templates", "lrt", "list-prompts", "lp":
+			return filterPrefix([]string{"--cursor", "--page-size", "--no-follow"}, word), start
+		}
+
+		return nil, start
+	}
+}
@@ -10,7 +10,6 @@ import (
 
 var (
 	configFile string
-	serverName string
 	setDefault bool
 )
 
@@ -58,6 +57,18 @@ var configListCmd = &cobra.Command{
 					fmt.Printf("    Args:      %v\n", server.Args)
 				}
 			}
+			if server.Timeout != "" {
+				fmt.Printf("    Timeout:   %s\n", server.Timeout)
+			}
+			if server.ProtocolVersion != "" {
+				fmt.Printf("    Protocol:  %s\n", server.ProtocolVersion)
+			}
+			if len(server.Roots) > 0 {
+				fmt.Printf("    Roots:     %v\n", server.Roots)
+			}
+			if server.Sampling != nil {
+				fmt.Printf("    Sampling:  %s\n", describeSampling(*server.Sampling))
+			}
 			fmt.Println()
 		}
 
@@ -104,6 +115,20 @@ var configAddCmd = &cobra.Command{
 			Args:      commandArgs,
 		}
 
+		if cmd.Flags().Changed("timeout") {
+			server.Timeout = requestTimeout.String()
+		}
+		if protoVersion != "" {
+			server.ProtocolVersion = protoVersion
+		}
+		if len(rootPaths) > 0 {
+			server.Roots = rootPaths
+		}
+		if samplingFlagsChanged(cmd) {
+			sampling := samplingConfig
+			server.Sampling = &sampling
+		}
+
 		cfg.AddServer(name, server)
 
 		if setDefault {
@@ -217,6 +242,12 @@ var configShowCmd = &cobra.Command{
 					fmt.Printf("  Args:      %v\n", server.Args)
 				}
 			}
+			if server.Timeout != "" {
+				fmt.Printf("  Timeout:   %s\n", server.Timeout)
+			}
+			if server.ProtocolVersion != "" {
+				fmt.Printf("  Protocol:  %s\n", server.ProtocolVersion)
+			}
 		} else {
 			// Show current effective configuration
 			fmt.Println("Current Configuration:")
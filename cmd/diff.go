@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Unchanged lines shown around each change in a diff
+const diffContext = 2
+
+// Beyond this many compared line pairs, diffs fall back to printing the
+// whole new text
+const maxDiffCells = 4_000_000
+
+type diffOp int
+
+const (
+	diffEqual diffOp = iota
+	diffDelete
+	diffInsert
+)
+
+type diffLine struct {
+	op   diffOp
+	text string
+}
+
+// lineDiff returns the line-by-line edit script turning a into b, based on
+// the longest common subsequence of lines. It returns nil if the inputs are
+// too large to compare.
+func lineDiff(a, b []string) []diffLine {
+	if len(a)*len(b) > maxDiffCells {
+		return nil
+	}
+
+	// lcs[i][j] is the LCS length of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var lines []diffLine
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			lines = append(lines, diffLine{diffEqual, a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			lines = append(lines, diffLine{diffDelete, a[i]})
+			i++
+		default:
+			lines = append(lines, diffLine{diffInsert, b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		lines = append(lines, diffLine{diffDelete, a[i]})
+	}
+	for ; j < len(b); j++ {
+		lines = append(lines, diffLine{diffInsert, b[j]})
+	}
+	return lines
+}
+
+// printDiff writes the changed lines of a diff with a little context.
+// It reports whether anything changed.
+func printDiff(w io.Writer, oldText, newText string, color bool) bool {
+	if oldText == newText {
+		return false
+	}
+
+	lines := lineDiff(strings.Split(oldText, "\n"), strings.Split(newText, "\n"))
+	if lines == nil {
+		fmt.Fprintln(w, "(too large to diff, showing new content)")
+		fmt.Fprintln(w, newText)
+		return true
+	}
+
+	// Mark the lines close enough to a change to be printed
+	show := make([]bool, len(lines))
+	for i, l := range lines {
+		if l.op == diffEqual {
+			continue
+		}
+		for k := max(0, i-diffContext); k <= min(len(lines)-1, i+diffContext); k++ {
+			show[k] = true
+		}
+	}
+
+	skipped := false
+	for i, l := range lines {
+		if !show[i] {
+			skipped = true
+			continue
+		}
+		if skipped {
+			fmt.Fprintln(w, "  ...")
+			skipped = false
+		}
+
+		switch l.op {
+		case diffDelete:
+			fmt.Fprintln(w, colorize("- "+l.text, "\033[31m", color))
+		case diffInsert:
+			fmt.Fprintln(w, colorize("+ "+l.text, "\033[32m", color))
+		default:
+			fmt.Fprintln(w, "  "+l.text)
+		}
+	}
+	return true
+}
+
+func colorize(s, code string, color bool) string {
+	if !color {
+		return s
+	}
+	return code + s + colorReset
+}
@@ -0,0 +1,300 @@
+package cmd
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jkeresman01/mcp-client/client"
+	"github.com/jkeresman01/mcp-client/transport"
+)
+
+// manifestName is the file --all writes next to the mirrored resources
+const manifestName = "manifest.json"
+
+// manifest describes a mirror written by get-resource --all
+type manifest struct {
+	Server    *client.Implementation `json:"server,omitempty"`
+	Fetched   time.Time              `json:"fetched"`
+	Resources []manifestEntry        `json:"resources"`
+}
+
+type manifestEntry struct {
+	URI      string `json:"uri"`
+	Name     string `json:"name,omitempty"`
+	MimeType string `json:"mimeType,omitempty"`
+	Path     string `json:"path,omitempty"`
+	Size     int    `json:"size"`
+	SHA256   string `json:"sha256,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+// isDirTarget reports whether --output names a directory: one that exists,
+// or a path ending in a separator
+func isDirTarget(target string) bool {
+	if strings.HasSuffix(target, "/") || strings.HasSuffix(target, string(filepath.Separator)) {
+		return true
+	}
+	info, err := os.Stat(target)
+	return err == nil && info.IsDir()
+}
+
+// saveResource writes the contents of a resources/read result to target.
+// A directory target gets one file per content, named after its URI.
+func saveResource(result *client.ReadResourceResult, target string) error {
+	contents := result.Contents
+	if len(contents) == 0 {
+		return fmt.Errorf("the server returned no contents")
+	}
+
+	dir := isDirTarget(target)
+	if !dir && len(contents) > 1 {
+		return &transport.MCPError{
+			Operation: "get-resource",
+			Err:       fmt.Errorf("the resource has %d contents but --output is a file", len(contents)),
+			Hints: []string{
+				"Pass a directory instead, e.g. --output ./out/",
+			},
+		}
+	}
+
+	for _, content := range contents {
+		p := target
+		if dir {
+			p = filepath.Join(target, withExtension(resourceFileName(content.URI), content.MimeType))
+		}
+
+		size, _, err := writeContent(content, p)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Wrote %s to %s\n", formatBytes(size), p)
+	}
+	return nil
+}
+
+// writeContent decodes text or blob content and writes it to p, creating
+// parent directories. It returns the size and SHA-256 of what was written.
+func writeContent(content client.ResourceContents, p string) (int, string, error) {
+	data := []byte(content.Text)
+
+	if content.Blob != "" {
+		decoded, err := base64.StdEncoding.DecodeString(content.Blob)
+		if err != nil {
+			return 0, "", fmt.Errorf("invalid base64 blob for %s: %v", content.URI, err)
+		}
+		data = decoded
+	}
+
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		return 0, "", err
+	}
+	if err := os.WriteFile(p, data, 0644); err != nil {
+		return 0, "", err
+	}
+
+	sum := sha256.Sum256(data)
+	return len(data), hex.EncodeToString(sum[:]), nil
+}
+
+// resourceFileName picks a file name from the last segment of a URI, or
+// its host for URIs like mock://counter
+func resourceFileName(uri string) string {
+	if segments := uriSegments(uri); len(segments) > 0 {
+		return segments[len(segments)-1]
+	}
+	if u, err := url.Parse(uri); err == nil && u.Host != "" {
+		return sanitizeSegment(u.Host)
+	}
+	return "resource"
+}
+
+// mirrorPath maps a URI into a directory tree under dir, as
+// scheme/host/path, e.g. file:///etc/hosts -> dir/file/etc/hosts
+func mirrorPath(dir, uri string) string {
+	parts := []string{dir}
+
+	if u, err := url.Parse(uri); err == nil && u.Scheme != "" {
+		parts = append(parts, sanitizeSegment(u.Scheme))
+		if u.Host != "" {
+			parts = append(parts, sanitizeSegment(u.Host))
+		}
+	}
+
+	parts = append(parts, uriSegments(uri)...)
+	if len(parts) == 1 {
+		parts = append(parts, "index")
+	}
+	return filepath.Join(parts...)
+}
+
+// uriSegments splits the path of a URI (or its opaque part, for URIs like
+// mock:counter) into safe file name segments
+func uriSegments(uri string) []string {
+	p := uri
+	if u, err := url.Parse(uri); err == nil {
+		p = u.Path
+		if p == "" {
+			p = u.Opaque
+		}
+	}
+
+	var segments []string
+	for _, s := range strings.Split(path.Clean("/"+p), "/") {
+		if s = sanitizeSegment(s); s != "" {
+			segments = append(segments, s)
+		}
+	}
+	return segments
+}
+
+// sanitizeSegment makes a path segment safe on every platform
+func sanitizeSegment(s string) string {
+	if s == "." || s == ".." {
+		return ""
+	}
+	return strings.Map(func(r rune) rune {
+		if r < 32 || strings.ContainsRune(`<>:"|?*\`, r) {
+			return '_'
+		}
+		return r
+	}, s)
+}
+
+// withExtension adds an extension from mimeType unless the name has one
+func withExtension(name, mimeType string) string {
+	if filepath.Ext(name) != "" {
+		return name
+	}
+	if mimeType == "" {
+		return name
+	}
+	return name + extensionFor(mimeType)
+}
+
+// mirrorResources reads every resource concurrently into dir and writes a
+// manifest. Failed resources are recorded in the manifest and reported.
+func mirrorResources(parent context.Context, s *client.Session, dir string, concurrency int) error {
+	ctx, cancel := requestContext(parent)
+	listed, err := s.ListAllResources(ctx, client.PageOptions{})
+	cancel()
+	if err != nil {
+		return transport.WrapError("list-resources", err)
+	}
+
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	var (
+		mu      sync.Mutex
+		entries []manifestEntry
+		failed  int
+		wg      sync.WaitGroup
+	)
+	jobs := make(chan client.Resource)
+
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for resource := range jobs {
+				results := mirrorResource(parent, s, dir, resource)
+
+				mu.Lock()
+				for _, e := range results {
+					if e.Error != "" {
+						failed++
+						fmt.Printf("  %s: %s\n", e.URI, e.Error)
+					} else {
+						fmt.Printf("  %s -> %s (%s)\n", e.URI, e.Path, formatBytes(e.Size))
+					}
+				}
+				entries = append(entries, results...)
+				mu.Unlock()
+			}
+		}()
+	}
+
+	fmt.Printf("Mirroring %d resources to %s\n", len(listed.Items), dir)
+	for _, resource := range listed.Items {
+		jobs <- resource
+	}
+	close(jobs)
+	wg.Wait()
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].URI < entries[j].URI })
+
+	info := s.ServerInfo()
+	m := manifest{
+		Server:    &info,
+		Fetched:   time.Now().UTC(),
+		Resources: entries,
+	}
+	data, _ := json.MarshalIndent(m, "", "  ")
+	manifestPath := filepath.Join(dir, manifestName)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(manifestPath, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write manifest: %v", err)
+	}
+	fmt.Printf("Wrote manifest to %s\n", manifestPath)
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d resources could not be saved, see %s", failed, len(entries), manifestPath)
+	}
+	return nil
+}
+
+// mirrorResource reads one listed resource and writes each of its contents
+func mirrorResource(parent context.Context, s *client.Session, dir string, resource client.Resource) []manifestEntry {
+	uri, name, listedMime := resource.URI, resource.Name, resource.MimeType
+
+	ctx, cancel := requestContext(parent)
+	defer cancel()
+
+	result, err := s.ReadResource(ctx, uri)
+	if err != nil {
+		return []manifestEntry{{URI: uri, Name: name, MimeType: listedMime, Error: err.Error()}}
+	}
+
+	if len(result.Contents) == 0 {
+		return []manifestEntry{{URI: uri, Name: name, MimeType: listedMime, Error: "no contents"}}
+	}
+
+	var entries []manifestEntry
+	for _, content := range result.Contents {
+		e := manifestEntry{URI: uri, Name: name, MimeType: listedMime}
+		if content.URI != "" {
+			e.URI = content.URI
+		}
+		if content.MimeType != "" {
+			e.MimeType = content.MimeType
+		}
+
+		p := withExtension(mirrorPath(dir, e.URI), e.MimeType)
+		size, sum, err := writeContent(content, p)
+		if err != nil {
+			e.Error = err.Error()
+		} else {
+			e.Path, _ = filepath.Rel(dir, p)
+			e.Path = filepath.ToSlash(e.Path)
+			e.Size = size
+			e.SHA256 = sum
+		}
+		entries = append(entries, e)
+	}
+	return entries
+}
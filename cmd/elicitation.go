@@ -0,0 +1,446 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/mail"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jkeresman01/mcp-client/client"
+	"github.com/jkeresman01/mcp-client/transport"
+)
+
+var (
+	elicitAnswersFile string
+	elicitAnswers     []string
+)
+
+// installElicitation answers elicitation/create from --elicit-answers and
+// --elicit when given, and with a terminal form when there is a user to
+// ask. Otherwise the elicitation capability is not advertised.
+func installElicitation(s *client.Session) error {
+	answers, err := loadElicitAnswers()
+	if err != nil {
+		return &transport.MCPError{
+			Operation: "loading elicitation answers",
+			Err:       err,
+			Hints: []string{
+				"--elicit-answers takes a JSON object of field values, e.g. {\"name\":\"Alice\",\"age\":30}",
+				"--elicit takes key=value, e.g. --elicit name=Alice",
+			},
+		}
+	}
+
+	switch {
+	case answers != nil:
+		s.SetElicitationHandler(func(ctx context.Context, req *client.ElicitRequest) (*client.ElicitResult, error) {
+			return answerElicitation(req, answers), nil
+		})
+
+	case canAsk():
+		s.SetElicitationHandler(func(ctx context.Context, req *client.ElicitRequest) (*client.ElicitResult, error) {
+			if err := userInput.ask(ctx); err != nil {
+				return nil, err
+			}
+			defer userInput.done()
+			return fillForm(ctx, req), nil
+		})
+	}
+	return nil
+}
+
+// loadElicitAnswers merges the answers file with the --elicit flags, which
+// win. It returns nil when neither was given.
+func loadElicitAnswers() (map[string]interface{}, error) {
+	if elicitAnswersFile == "" && len(elicitAnswers) == 0 {
+		return nil, nil
+	}
+
+	answers := map[string]interface{}{}
+	if elicitAnswersFile != "" {
+		data, err := os.ReadFile(elicitAnswersFile)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(data, &answers); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %v", elicitAnswersFile, err)
+		}
+	}
+
+	for _, pair := range elicitAnswers {
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid --elicit %q: expected key=value", pair)
+		}
+		answers[key] = value
+	}
+	return answers, nil
+}
+
+// answerElicitation fills the requested fields from prepared answers.
+// Answers for fields the server didn't ask for are ignored; if a required
+// field is missing or an answer is invalid the request is cancelled.
+func answerElicitation(req *client.ElicitRequest, answers map[string]interface{}) *client.ElicitResult {
+	content := map[string]interface{}{}
+	var problems []string
+
+	for _, f := range formFields(req) {
+		value, ok := answers[f.name]
+		if !ok {
+			if f.def != nil {
+				content[f.name] = f.def
+			} else if f.required {
+				problems = append(problems, fmt.Sprintf("%s: no answer for required field", f.name))
+			}
+			continue
+		}
+
+		v, err := f.check(value)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", f.name, err))
+			continue
+		}
+		content[f.name] = v
+	}
+
+	if len(problems) > 0 {
+		fmt.Fprintf(diagnostics(), "Warning: cancelled elicitation %q:\n  %s\n", req.Message, strings.Join(problems, "\n  "))
+		return &client.ElicitResult{Action: client.ElicitCancel}
+	}
+	return &client.ElicitResult{Action: client.ElicitAccept, Content: content}
+}
+
+// fillForm asks for each field in turn, then whether to submit. Ctrl-C or
+// Ctrl-D at any point cancels, and so does the server cancelling the
+// request, which ctx reports.
+func fillForm(ctx context.Context, req *client.ElicitRequest) *client.ElicitResult {
+	cancelled := &client.ElicitResult{Action: client.ElicitCancel}
+	dropped := func() *client.ElicitResult {
+		if ctx.Err() != nil {
+			fmt.Fprintln(diagnostics(), "The server withdrew the question")
+		}
+		return cancelled
+	}
+
+	fmt.Fprintln(diagnostics())
+	fmt.Fprintf(diagnostics(), "The server asks: %s\n", req.Message)
+	fmt.Fprintln(diagnostics(), "(* marks required fields, Ctrl-C cancels)")
+
+	content := map[string]interface{}{}
+	for _, f := range formFields(req) {
+		if f.description != "" {
+			fmt.Fprintf(diagnostics(), "  %s\n", f.description)
+		}
+		for i, o := range f.options {
+			fmt.Fprintf(diagnostics(), "    %d) %s\n", i+1, o.label)
+		}
+
+		for {
+			input, err := askUser(ctx, f.prompt())
+			if err != nil {
+				return dropped()
+			}
+
+			input = strings.TrimSpace(input)
+			if input == "" {
+				if f.def != nil {
+					content[f.name] = f.def
+				} else if f.required {
+					fmt.Fprintln(diagnostics(), "  This field is required")
+					continue
+				}
+				break
+			}
+
+			v, err := f.parse(input)
+			if err != nil {
+				fmt.Fprintf(diagnostics(), "  %v\n", err)
+				continue
+			}
+			content[f.name] = v
+			break
+		}
+	}
+
+	output, _ := json.MarshalIndent(content, "", "  ")
+	fmt.Fprintf(diagnostics(), "Answers:\n%s\n", string(output))
+
+	for {
+		answer, err := askUser(ctx, "Submit? [a]ccept, [d]ecline, [c]ancel: ")
+		if err != nil {
+			return dropped()
+		}
+
+		switch strings.ToLower(strings.TrimSpace(answer)) {
+		case "a", "accept", "y", "yes":
+			return &client.ElicitResult{Action: client.ElicitAccept, Content: content}
+		case "d", "decline", "n", "no":
+			return &client.ElicitResult{Action: client.ElicitDecline}
+		case "c", "cancel":
+			return cancelled
+		}
+	}
+}
+
+// formField is one property of an elicitation schema. Elicitation only
+// allows flat objects of strings, numbers, integers, booleans and enums.
+type formField struct {
+	name        string
+	title       string
+	description string
+	typ         string
+	required    bool
+	def         interface{}
+	options     []formOption
+	schema      map[string]interface{}
+}
+
+type formOption struct {
+	value string
+	label string
+}
+
+// formFields lists the requested fields in the order the server sent them
+func formFields(req *client.ElicitRequest) []formField {
+	properties, _ := req.RequestedSchema["properties"].(map[string]interface{})
+
+	required := map[string]bool{}
+	if list, ok := req.RequestedSchema["required"].([]interface{}); ok {
+		for _, name := range list {
+			if n, ok := name.(string); ok {
+				required[n] = true
+			}
+		}
+	}
+
+	names := req.Properties
+	if len(names) != len(properties) {
+		names = sortedKeys(properties)
+	}
+
+	fields := make([]formField, 0, len(names))
+	for _, name := range names {
+		schema, _ := properties[name].(map[string]interface{})
+
+		f := formField{
+			name:     name,
+			required: required[name],
+			def:      schema["default"],
+			schema:   schema,
+		}
+		f.title, _ = schema["title"].(string)
+		f.description, _ = schema["description"].(string)
+		f.typ, _ = schema["type"].(string)
+		f.options = enumOptions(schema)
+
+		fields = append(fields, f)
+	}
+	return fields
+}
+
+// enumOptions reads enum (with the legacy enumNames labels) or oneOf
+// const/title pairs
+func enumOptions(schema map[string]interface{}) []formOption {
+	var options []formOption
+
+	if values, ok := schema["enum"].([]interface{}); ok {
+		labels, _ := schema["enumNames"].([]interface{})
+		for i, v := range values {
+			o := formOption{value: fmt.Sprint(v), label: fmt.Sprint(v)}
+			if i < len(labels) {
+				o.label = fmt.Sprintf("%v (%s)", labels[i], o.value)
+			}
+			options = append(options, o)
+		}
+		return options
+	}
+
+	if choices, ok := schema["oneOf"].([]interface{}); ok {
+		for _, c := range choices {
+			m, _ := c.(map[string]interface{})
+			value, ok := m["const"]
+			if !ok {
+				continue
+			}
+			o := formOption{value: fmt.Sprint(value), label: fmt.Sprint(value)}
+			if title, ok := m["title"].(string); ok {
+				o.label = fmt.Sprintf("%s (%s)", title, o.value)
+			}
+			options = append(options, o)
+		}
+	}
+	return options
+}
+
+// prompt renders the question for a field, e.g. "Age* (integer, 0-120): "
+func (f formField) prompt() string {
+	label := f.title
+	if label == "" {
+		label = f.name
+	}
+	if f.required {
+		label += "*"
+	}
+
+	var hints []string
+	switch {
+	case len(f.options) > 0:
+		hints = append(hints, "choose 1-"+strconv.Itoa(len(f.options)))
+	case f.typ == "boolean":
+		hints = append(hints, "y/n")
+	case f.typ != "":
+		hints = append(hints, f.typ)
+	}
+	if format, ok := f.schema["format"].(string); ok {
+		hints = append(hints, format)
+	}
+	if r := f.rangeHint(); r != "" {
+		hints = append(hints, r)
+	}
+
+	prompt := "  " + label
+	if len(hints) > 0 {
+		prompt += " (" + strings.Join(hints, ", ") + ")"
+	}
+	if f.def != nil {
+		prompt += fmt.Sprintf(" [%v]", f.def)
+	}
+	return prompt + ": "
+}
+
+func (f formField) rangeHint() string {
+	lo, hasLo := f.number("minimum")
+	hi, hasHi := f.number("maximum")
+	unit := ""
+	if f.typ == "string" {
+		lo, hasLo = f.number("minLength")
+		hi, hasHi = f.number("maxLength")
+		unit = " chars"
+	}
+
+	switch {
+	case hasLo && hasHi:
+		return fmt.Sprintf("%v-%v%s", lo, hi, unit)
+	case hasLo:
+		return fmt.Sprintf(">= %v%s", lo, unit)
+	case hasHi:
+		return fmt.Sprintf("<= %v%s", hi, unit)
+	}
+	return ""
+}
+
+func (f formField) number(key string) (float64, bool) {
+	n, ok := f.schema[key].(float64)
+	return n, ok
+}
+
+// check validates a prepared answer, which may already be typed JSON
+func (f formField) check(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case string:
+		return f.parse(v)
+	case float64:
+		return f.parse(strconv.FormatFloat(v, 'f', -1, 64))
+	case bool:
+		return f.parse(strconv.FormatBool(v))
+	}
+	return nil, fmt.Errorf("expected a %s, got %v", f.typ, value)
+}
+
+// parse converts typed input to the field's type and checks its constraints
+func (f formField) parse(input string) (interface{}, error) {
+	if len(f.options) > 0 {
+		for _, o := range f.options {
+			if o.value == input {
+				return o.value, nil
+			}
+		}
+		if i, err := strconv.Atoi(input); err == nil && i >= 1 && i <= len(f.options) {
+			return f.options[i-1].value, nil
+		}
+		return nil, fmt.Errorf("choose one of 1-%d", len(f.options))
+	}
+
+	switch f.typ {
+	case "boolean":
+		switch strings.ToLower(input) {
+		case "y", "yes", "true", "1":
+			return true, nil
+		case "n", "no", "false", "0":
+			return false, nil
+		}
+		return nil, fmt.Errorf("answer y or n")
+
+	case "number", "integer":
+		n, err := strconv.ParseFloat(input, 64)
+		if err != nil || math.IsNaN(n) || math.IsInf(n, 0) {
+			return nil, fmt.Errorf("%q is not a number", input)
+		}
+		if f.typ == "integer" && n != math.Trunc(n) {
+			return nil, fmt.Errorf("%q is not a whole number", input)
+		}
+		if lo, ok := f.number("minimum"); ok && n < lo {
+			return nil, fmt.Errorf("must be at least %v", lo)
+		}
+		if hi, ok := f.number("maximum"); ok && n > hi {
+			return nil, fmt.Errorf("must be at most %v", hi)
+		}
+		if f.typ == "integer" {
+			return int64(n), nil
+		}
+		return n, nil
+	}
+
+	length := float64(len([]rune(input)))
+	if lo, ok := f.number("minLength"); ok && length < lo {
+		return nil, fmt.Errorf("must be at least %v characters", lo)
+	}
+	if hi, ok := f.number("maxLength"); ok && length > hi {
+		return nil, fmt.Errorf("must be at most %v characters", hi)
+	}
+	if format, ok := f.schema["format"].(string); ok {
+		if err := checkFormat(format, input); err != nil {
+			return nil, err
+		}
+	}
+	return input, nil
+}
+
+// checkFormat validates the string formats elicitation schemas may use
+func checkFormat(format, value string) error {
+	switch format {
+	case "email":
+		if _, err := mail.ParseAddress(value); err != nil || strings.Contains(value, "<") {
+			return fmt.Errorf("%q is not an email address", value)
+		}
+	case "uri":
+		if u, err := url.Parse(value); err != nil || u.Scheme == "" {
+			return fmt.Errorf("%q is not an absolute URI", value)
+		}
+	case "date":
+		if _, err := time.Parse(time.DateOnly, value); err != nil {
+			return fmt.Errorf("%q is not a date (YYYY-MM-DD)", value)
+		}
+	case "date-time":
+		if _, err := time.Parse(time.RFC3339, value); err != nil {
+			return fmt.Errorf("%q is not a date-time (RFC 3339)", value)
+		}
+	}
+	return nil
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
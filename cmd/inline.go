@@ -0,0 +1,220 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/color/palette"
+	"image/draw"
+	_ "image/gif"
+	_ "image/jpeg"
+	"image/png"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+// Inline image protocols
+const (
+	inlineKitty = "kitty"
+	inlineITerm = "iterm"
+	inlineSixel = "sixel"
+)
+
+// Images wider or taller than this are scaled down before sixel encoding
+const (
+	maxSixelWidth  = 800
+	maxSixelHeight = 600
+)
+
+// kittyChunkSize is the most base64 the kitty protocol takes per escape
+const kittyChunkSize = 4096
+
+var warnInlineOnce sync.Once
+
+// inlineProtocol resolves --inline to the protocol to use on out, or ""
+// to save images to files instead. "auto" looks at the environment of the
+// terminal emulator; tmux and screen are left alone since they swallow
+// the escapes.
+func inlineProtocol(mode string, out *os.File) string {
+	switch mode {
+	case inlineKitty, inlineITerm, inlineSixel:
+		return mode
+	case "none", "off":
+		return ""
+	case "auto", "":
+	default:
+		warnInlineOnce.Do(func() {
+			fmt.Fprintf(diagnostics(), "Warning: unknown --inline mode %q, expected auto, kitty, iterm, sixel or none\n", mode)
+		})
+		return ""
+	}
+
+	if !isTerminal(out) || os.Getenv("TMUX") != "" || strings.HasPrefix(os.Getenv("TERM"), "screen") {
+		return ""
+	}
+
+	term := os.Getenv("TERM")
+	switch {
+	case os.Getenv("KITTY_WINDOW_ID") != "" || term == "xterm-kitty" || term == "xterm-ghostty":
+		return inlineKitty
+	case os.Getenv("TERM_PROGRAM") == "iTerm.app" || os.Getenv("TERM_PROGRAM") == "WezTerm":
+		return inlineITerm
+	case strings.Contains(term, "sixel") || strings.HasPrefix(term, "foot") || term == "mlterm":
+		return inlineSixel
+	}
+	return ""
+}
+
+// showInline draws an image in the terminal with the given protocol
+func showInline(w io.Writer, protocol, mimeType string, data []byte) error {
+	switch protocol {
+	case inlineITerm:
+		fmt.Fprintf(w, "\033]1337;File=inline=1;size=%d;preserveAspectRatio=1:%s\a\n",
+			len(data), base64.StdEncoding.EncodeToString(data))
+		return nil
+
+	case inlineKitty:
+		// kitty decodes PNG itself; anything else is converted first
+		if mimeType != "image/png" {
+			img, _, err := image.Decode(bytes.NewReader(data))
+			if err != nil {
+				return err
+			}
+			var buf bytes.Buffer
+			if err := png.Encode(&buf, img); err != nil {
+				return err
+			}
+			data = buf.Bytes()
+		}
+		writeKitty(w, data)
+		return nil
+
+	case inlineSixel:
+		img, _, err := image.Decode(bytes.NewReader(data))
+		if err != nil {
+			return err
+		}
+		return writeSixel(w, img)
+	}
+
+	return fmt.Errorf("unknown inline protocol %q", protocol)
+}
+
+// writeKitty sends a PNG with the kitty graphics protocol, in chunks
+func writeKitty(w io.Writer, pngData []byte) {
+	encoded := base64.StdEncoding.EncodeToString(pngData)
+
+	first := true
+	for len(encoded) > 0 {
+		chunk := encoded
+		if len(chunk) > kittyChunkSize {
+			chunk = chunk[:kittyChunkSize]
+		}
+		encoded = encoded[len(chunk):]
+
+		more := 0
+		if len(encoded) > 0 {
+			more = 1
+		}
+		if first {
+			fmt.Fprintf(w, "\033_Ga=T,f=100,m=%d;%s\033\\", more, chunk)
+			first = false
+		} else {
+			fmt.Fprintf(w, "\033_Gm=%d;%s\033\\", more, chunk)
+		}
+	}
+	fmt.Fprintln(w)
+}
+
+// writeSixel encodes an image as sixels with a 256 color palette
+func writeSixel(w io.Writer, img image.Image) error {
+	img = scaleDown(img, maxSixelWidth, maxSixelHeight)
+
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+
+	pal := palette.Plan9
+	paletted := image.NewPaletted(image.Rect(0, 0, width, height), pal)
+	draw.FloydSteinberg.Draw(paletted, paletted.Bounds(), img, bounds.Min)
+
+	out := bufio.NewWriter(w)
+	fmt.Fprintf(out, "\033Pq\"1;1;%d;%d", width, height)
+	for i, c := range pal {
+		r, g, b, _ := c.RGBA()
+		fmt.Fprintf(out, "#%d;2;%d;%d;%d", i, r*100/0xffff, g*100/0xffff, b*100/0xffff)
+	}
+
+	// Each band is six pixel rows; every color used in the band is drawn
+	// in its own pass over the row, returning to the start with "$"
+	bits := make([][]byte, len(pal))
+	for top := 0; top < height; top += 6 {
+		var used []int
+		for dy := 0; dy < 6 && top+dy < height; dy++ {
+			row := paletted.Pix[(top+dy)*paletted.Stride:]
+			for x := 0; x < width; x++ {
+				idx := int(row[x])
+				if bits[idx] == nil {
+					bits[idx] = make([]byte, width)
+					used = append(used, idx)
+				}
+				bits[idx][x] |= 1 << dy
+			}
+		}
+
+		for _, idx := range used {
+			fmt.Fprintf(out, "#%d", idx)
+			writeSixelRun(out, bits[idx])
+			out.WriteByte('$')
+			bits[idx] = nil
+		}
+		out.WriteByte('-')
+	}
+
+	out.WriteString("\033\\\n")
+	return out.Flush()
+}
+
+// writeSixelRun writes one color's pass over a band, run-length encoded
+func writeSixelRun(out *bufio.Writer, row []byte) {
+	for x := 0; x < len(row); {
+		n := 1
+		for x+n < len(row) && row[x+n] == row[x] {
+			n++
+		}
+
+		ch := byte(63 + row[x])
+		if n > 3 {
+			fmt.Fprintf(out, "!%d%c", n, ch)
+		} else {
+			for i := 0; i < n; i++ {
+				out.WriteByte(ch)
+			}
+		}
+		x += n
+	}
+}
+
+// scaleDown shrinks img to fit maxW x maxH, keeping its aspect ratio, with
+// nearest-neighbor sampling
+func scaleDown(img image.Image, maxW, maxH int) image.Image {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if w <= maxW && h <= maxH {
+		return img
+	}
+
+	scale := min(float64(maxW)/float64(w), float64(maxH)/float64(h))
+	nw, nh := max(int(float64(w)*scale), 1), max(int(float64(h)*scale), 1)
+
+	scaled := image.NewRGBA(image.Rect(0, 0, nw, nh))
+	for y := 0; y < nh; y++ {
+		for x := 0; x < nw; x++ {
+			scaled.Set(x, y, img.At(b.Min.X+x*w/nw, b.Min.Y+y*h/nh))
+		}
+	}
+	return scaled
+}
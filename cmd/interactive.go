@@ -1,12 +1,15 @@
 package cmd
 
 import (
-	"bufio"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 
+	"github.com/jkeresman01/mcp-client/client"
 	"github.com/jkeresman01/mcp-client/transport"
 	"github.com/spf13/cobra"
 )
@@ -21,37 +24,51 @@ or 'exit' to quit.`,
 }
 
 func init() {
+	interactiveCmd.Flags().BoolVar(&noValidate, "no-validate", false, "Don't check tool arguments against the tool's inputSchema before calling it")
 	rootCmd.AddCommand(interactiveCmd)
 }
 
 func runInteractive(cmd *cobra.Command, args []string) error {
-	// Get transport once for the session
-	t, err := getTransport()
+	interactiveMode = true
+
+	// One session for the whole REPL
+	s, err := newSession()
 	if err != nil {
 		return err
 	}
-	defer t.Close()
+	defer s.Close()
 
 	// Try to initialize the connection
-	if err := initializeConnection(t); err != nil {
+	if err := initializeSession(cmd.Context(), s); err != nil {
 		fmt.Printf("Warning: Failed to initialize connection: %v\n", err)
-		fmt.Println("You can still try commands, but the server may not be ready.\n")
+		fmt.Println("You can still try commands, but the server may not be ready.")
+		fmt.Println()
 	} else {
-		fmt.Println("Connected successfully!\n")
+		fmt.Println("Connected successfully!")
+		fmt.Println()
 	}
 
 	printWelcome()
 
-	scanner := bufio.NewScanner(os.Stdin)
+	reader := newLineReader(replCompleter(s))
 
 	for {
-		fmt.Print("mcp> ")
-
-		if !scanner.Scan() {
+		// Server requests asking the user get stdin first; the prompt
+		// comes back afterwards with the line as it was
+		ctx, release := userInput.prompt()
+		input, err := reader.ReadLine(ctx, "mcp> ")
+		release()
+		if err == errInterrupted || errors.Is(err, context.Canceled) {
+			continue
+		}
+		if err == io.EOF {
 			break
 		}
+		if err != nil {
+			return fmt.Errorf("error reading input: %v", err)
+		}
 
-		line := strings.TrimSpace(scanner.Text())
+		line := strings.TrimSpace(input)
 		if line == "" {
 			continue
 		}
@@ -61,15 +78,11 @@ func runInteractive(cmd *cobra.Command, args []string) error {
 			break
 		}
 
-		if err := handleInteractiveCommand(t, line); err != nil {
+		if err := handleInteractiveCommand(s, line); err != nil {
 			fmt.Printf("%v\n", err)
 		}
 	}
 
-	if err := scanner.Err(); err != nil {
-		return fmt.Errorf("error reading input: %v", err)
-	}
-
 	return nil
 }
 
@@ -78,17 +91,29 @@ func printWelcome() {
 	fmt.Println()
 	fmt.Println("Available commands:")
 	fmt.Println("  help                          - Show this help message")
-	fmt.Println("  list-tools                    - List all available tools")
-	fmt.Println("  list-resources                - List all available resources")
-	fmt.Println("  list-prompts                  - List all available prompts")
-	fmt.Println("  call <tool> <args>            - Call a tool with JSON args")
+	fmt.Println("  list-tools [paging]           - List all available tools")
+	fmt.Println("  list-resources [paging]       - List all available resources")
+	fmt.Println("  list-resource-templates       - List resource templates (accepts paging)")
+	fmt.Println("  list-prompts [paging]         - List all available prompts")
+	fmt.Println("  call <tool> [args]            - Call a tool (JSON or key=value args, asked for if none)")
 	fmt.Println("  get-resource <uri>            - Get resource content")
-	fmt.Println("  get-prompt <name> [args]      - Get prompt details")
+	fmt.Println("  get-resource <template> k=v   - Expand a resource template and get it")
+	fmt.Println("  watch <uri> [--diff]          - Reprint a resource when it changes (Ctrl-C stops)")
+	fmt.Println("  get-prompt <name> [args]      - Get prompt details (JSON or key=value args, asked for if none)")
+	fmt.Println("  roots [add|remove <path>]     - List or change the roots exposed to the server")
 	fmt.Println("  exit, quit, q                 - Exit interactive mode")
 	fmt.Println()
+	fmt.Println("Paging options: --cursor <cursor>, --page-size <n>, --no-follow")
+	fmt.Println()
 	fmt.Println("Examples:")
 	fmt.Println("  call calculator {\"op\":\"add\",\"a\":5,\"b\":3}")
+	fmt.Println("  call search query=golang limit=10 tags[]=go")
+	fmt.Println("  call search                   (asks for each argument)")
 	fmt.Println("  get-resource file:///path/to/file")
+	fmt.Println("  get-resource db://{table}/{id} table=users id=42")
+	fmt.Println("  get-prompt code_review language=go")
+	fmt.Println()
+	fmt.Println("Press Tab to complete commands, names and arguments.")
 	fmt.Println()
 }
 
@@ -97,26 +122,7 @@ func shouldExit(line string) bool {
 	return lower == "exit" || lower == "quit" || lower == "q"
 }
 
-func initializeConnection(t transport.Transport) error {
-	req := transport.RPCRequest{
-		JSONRPC: "2.0",
-		ID:      1,
-		Method:  "initialize",
-		Params: map[string]interface{}{
-			"protocolVersion": "2024-11-05",
-			"clientInfo": map[string]string{
-				"name":    "mcp-client",
-				"version": "0.2.0",
-			},
-			"capabilities": map[string]interface{}{},
-		},
-	}
-
-	_, err := t.Send(req)
-	return err
-}
-
-func handleInteractiveCommand(t transport.Transport, line string) error {
+func handleInteractiveCommand(s *client.Session, line string) error {
 	parts := parseCommandLine(line)
 	if len(parts) == 0 {
 		return nil
@@ -130,41 +136,71 @@ func handleInteractiveCommand(t transport.Transport, line string) error {
 		return nil
 
 	case "list-tools", "lt":
-		return listToolsInteractive(t)
+		return listToolsInteractive(s, parts[1:])
 
 	case "list-resources", "lr":
-		return listResourcesInteractive(t)
+		return listResourcesInteractive(s, parts[1:])
+
+	case "list-resource-templates", "lrt":
+		return listResourceTemplatesInteractive(s, parts[1:])
 
 	case "list-prompts", "lp":
-		return listPromptsInteractive(t)
+		return listPromptsInteractive(s, parts[1:])
 
 	case "call", "c":
 		if len(parts) < 2 {
-			return fmt.Errorf("usage: call <tool-name> <json-args>\nExample: call calculator {\"op\":\"add\",\"a\":5,\"b\":3}")
+			return fmt.Errorf("usage: call <tool-name> [json-args | key=value ...]\nExample: call calculator {\"op\":\"add\",\"a\":5,\"b\":3}")
 		}
 		toolName := parts[1]
-		args := "{}"
+		args := ""
+		var pairs []string
 		if len(parts) >= 3 {
-			args = strings.Join(parts[2:], " ")
+			if strings.HasPrefix(parts[2], "{") {
+				args = strings.Join(parts[2:], " ")
+			} else {
+				pairs = parts[2:]
+			}
 		}
-		return callToolInteractive(t, toolName, args)
+		return callToolInteractive(s, toolName, args, pairs)
 
 	case "get-resource", "gr":
 		if len(parts) < 2 {
-			return fmt.Errorf("usage: get-resource <resource-uri>")
+			return fmt.Errorf("usage: get-resource <resource-uri>\n       get-resource <uri-template> [key=value ...]")
+		}
+		uri := parts[1]
+		if strings.Contains(uri, "{") {
+			expanded, err := expandTemplate(uri, parts[2:])
+			if err != nil {
+				return err
+			}
+			uri = expanded
+		}
+		return getResourceInteractive(s, uri)
+
+	case "watch", "w":
+		if len(parts) < 2 {
+			return fmt.Errorf("usage: watch <resource-uri> [--diff]")
 		}
-		return getResourceInteractive(t, parts[1])
+		showDiff := len(parts) >= 3 && parts[2] == "--diff"
+		return watchResource(context.Background(), s, parts[1], showDiff)
 
 	case "get-prompt", "gp":
 		if len(parts) < 2 {
-			return fmt.Errorf("usage: get-prompt <prompt-name> [json-args]")
+			return fmt.Errorf("usage: get-prompt <prompt-name> [json-args | key=value ...]")
 		}
 		promptName := parts[1]
-		args := "{}"
+		args := ""
 		if len(parts) >= 3 {
-			args = strings.Join(parts[2:], " ")
+			if strings.HasPrefix(parts[2], "{") {
+				args = strings.Join(parts[2:], " ")
+			} else {
+				args = strings.Join(parts[2:], ",")
+			}
 		}
-		return getPromptInteractive(t, promptName, args)
+		return getPromptInteractive(s, promptName, args)
+
+	case "roots":
+		return rootsInteractive(s, parts[1:])
 
 	default:
 		return fmt.Errorf("unknown command: %s\nType 'help' for available commands", command)
@@ -212,156 +248,174 @@ func parseCommandLine(line string) []string {
 	return parts
 }
 
-func listToolsInteractive(t transport.Transport) error {
-	req := transport.RPCRequest{
-		JSONRPC: "2.0",
-		ID:      2,
-		Method:  "tools/list",
-		Params:  map[string]interface{}{},
+// interactiveError keeps server errors short in the REPL and adds
+// troubleshooting hints for everything else
+func interactiveError(operation string, err error) error {
+	var rpcErr *transport.RPCError
+	if errors.As(err, &rpcErr) {
+		return fmt.Errorf("server error: %v", rpcErr)
 	}
+	return transport.WrapError(operation, err)
+}
 
-	resp, err := t.Send(req)
+func listToolsInteractive(s *client.Session, args []string) error {
+	opts, err := parsePagingArgs("list-tools", args)
 	if err != nil {
-		return transport.WrapError("list-tools", err)
+		return err
 	}
 
-	if resp.Error != nil {
-		return fmt.Errorf("server error: %v", resp.Error)
+	ctx, cancel := requestContext(context.Background())
+	defer cancel()
+
+	result, err := s.ListAllTools(ctx, opts)
+	if err != nil {
+		return interactiveError("list-tools", err)
 	}
 
-	output, _ := json.MarshalIndent(resp.Result, "", "  ")
+	output, _ := json.MarshalIndent(listOutput("tools", result), "", "  ")
 	fmt.Printf("Tools:\n%s\n", string(output))
+	printMoreHint(result)
 	return nil
 }
 
-func listResourcesInteractive(t transport.Transport) error {
-	req := transport.RPCRequest{
-		JSONRPC: "2.0",
-		ID:      10,
-		Method:  "resources/list",
-		Params:  map[string]interface{}{},
-	}
-
-	resp, err := t.Send(req)
+func listResourcesInteractive(s *client.Session, args []string) error {
+	opts, err := parsePagingArgs("list-resources", args)
 	if err != nil {
-		return transport.WrapError("list-resources", err)
+		return err
 	}
 
-	if resp.Error != nil {
-		return fmt.Errorf("server error: %v", resp.Error)
+	ctx, cancel := requestContext(context.Background())
+	defer cancel()
+
+	result, err := s.ListAllResources(ctx, opts)
+	if err != nil {
+		return interactiveError("list-resources", err)
 	}
 
-	output, _ := json.MarshalIndent(resp.Result, "", "  ")
+	output, _ := json.MarshalIndent(listOutput("resources", result), "", "  ")
 	fmt.Printf("Resources:\n%s\n", string(output))
+	printMoreHint(result)
 	return nil
 }
 
-func listPromptsInteractive(t transport.Transport) error {
-	req := transport.RPCRequest{
-		JSONRPC: "2.0",
-		ID:      20,
-		Method:  "prompts/list",
-		Params:  map[string]interface{}{},
+func listResourceTemplatesInteractive(s *client.Session, args []string) error {
+	opts, err := parsePagingArgs("list-resource-templates", args)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := requestContext(context.Background())
+	defer cancel()
+
+	result, err := s.ListAllResourceTemplates(ctx, opts)
+	if err != nil {
+		return interactiveError("list-resource-templates", err)
 	}
 
-	resp, err := t.Send(req)
+	output, _ := json.MarshalIndent(listOutput("resourceTemplates", result), "", "  ")
+	fmt.Printf("Resource templates:\n%s\n", string(output))
+	printMoreHint(result)
+	return nil
+}
+
+func listPromptsInteractive(s *client.Session, args []string) error {
+	opts, err := parsePagingArgs("list-prompts", args)
 	if err != nil {
-		return transport.WrapError("list-prompts", err)
+		return err
 	}
 
-	if resp.Error != nil {
-		return fmt.Errorf("server error: %v", resp.Error)
+	ctx, cancel := requestContext(context.Background())
+	defer cancel()
+
+	result, err := s.ListAllPrompts(ctx, opts)
+	if err != nil {
+		return interactiveError("list-prompts", err)
 	}
 
-	output, _ := json.MarshalIndent(resp.Result, "", "  ")
+	output, _ := json.MarshalIndent(listOutput("prompts", result), "", "  ")
 	fmt.Printf("Prompts:\n%s\n", string(output))
+	printMoreHint(result)
 	return nil
 }
 
-func callToolInteractive(t transport.Transport, toolName, argsJSON string) error {
+// callToolInteractive calls a tool with JSON or key=value arguments. With
+// neither it asks for each argument in the tool's inputSchema.
+func callToolInteractive(s *client.Session, toolName, argsJSON string, pairs []string) error {
 	var parsedArgs map[string]interface{}
-	if err := json.Unmarshal([]byte(argsJSON), &parsedArgs); err != nil {
-		return transport.NewInvalidArgumentsError(err.Error())
+	if argsJSON == "" && len(pairs) == 0 {
+		ctx, cancel := requestContext(context.Background())
+		args, err := askToolArgs(ctx, s, toolName)
+		cancel()
+		if err != nil {
+			return err
+		}
+		parsedArgs = args
+	} else if argsJSON != "" {
+		if err := json.Unmarshal([]byte(argsJSON), &parsedArgs); err != nil {
+			return transport.NewInvalidArgumentsError(err.Error())
+		}
 	}
 
-	req := transport.RPCRequest{
-		JSONRPC: "2.0",
-		ID:      3,
-		Method:  "tools/call",
-		Params: map[string]interface{}{
-			"name":      toolName,
-			"arguments": parsedArgs,
-		},
-	}
+	ctx, cancel := requestContext(context.Background())
+	defer cancel()
 
-	resp, err := t.Send(req)
+	parsedArgs, err := buildToolArgs(ctx, s, toolName, parsedArgs, pairs)
 	if err != nil {
-		return transport.WrapError("call-tool", err)
+		return err
 	}
 
-	if resp.Error != nil {
-		return fmt.Errorf("server error: %v", resp.Error)
+	if err := validateToolArgs(ctx, s, toolName, parsedArgs); err != nil {
+		return err
 	}
 
-	output, _ := json.MarshalIndent(resp.Result, "", "  ")
-	fmt.Printf(" Tool Result:\n%s\n", string(output))
-	return nil
+	progress := newProgressRenderer(os.Stdout)
+	result, err := s.CallToolWithProgress(ctx, toolName, parsedArgs, progress.Update)
+	progress.Finish()
+	if err != nil {
+		return interactiveError("call-tool", err)
+	}
+
+	checkStructuredOutput(ctx, s, toolName, result, false)
+	return renderToolResult(toolName, result, s.Features())
 }
 
-func getResourceInteractive(t transport.Transport, uri string) error {
-	req := transport.RPCRequest{
-		JSONRPC: "2.0",
-		ID:      11,
-		Method:  "resources/read",
-		Params: map[string]interface{}{
-			"uri": uri,
-		},
-	}
+func getResourceInteractive(s *client.Session, uri string) error {
+	ctx, cancel := requestContext(context.Background())
+	defer cancel()
 
-	resp, err := t.Send(req)
+	result, err := s.ReadResource(ctx, uri)
 	if err != nil {
-		return transport.WrapError("get-resource", err)
+		return interactiveError("get-resource", err)
 	}
 
-	if resp.Error != nil {
-		return fmt.Errorf("server error: %v", resp.Error)
-	}
-
-	output, _ := json.MarshalIndent(resp.Result, "", "  ")
-	fmt.Printf("Resource:\n%s\n", string(output))
+	renderResource(result, s.Features())
 	return nil
 }
 
-func getPromptInteractive(t transport.Transport, name, argsJSON string) error {
-	params := map[string]interface{}{
-		"name": name,
+// getPromptInteractive gets a prompt with JSON or key=value arguments.
+// With neither it asks for each argument the prompt declares.
+func getPromptInteractive(s *client.Session, name, args string) error {
+	var parsedArgs map[string]interface{}
+	var err error
+	if args == "" {
+		ctx, cancel := requestContext(context.Background())
+		parsedArgs, err = askPromptArgs(ctx, s, name)
+		cancel()
+	} else {
+		parsedArgs, err = parsePromptArguments(args)
 	}
-
-	if argsJSON != "{}" {
-		var parsedArgs map[string]interface{}
-		if err := json.Unmarshal([]byte(argsJSON), &parsedArgs); err != nil {
-			return transport.NewInvalidArgumentsError(err.Error())
-		}
-		params["arguments"] = parsedArgs
+	if err != nil {
+		return err
 	}
 
-	req := transport.RPCRequest{
-		JSONRPC: "2.0",
-		ID:      21,
-		Method:  "prompts/get",
-		Params:  params,
-	}
+	ctx, cancel := requestContext(context.Background())
+	defer cancel()
 
-	resp, err := t.Send(req)
+	result, err := s.GetPrompt(ctx, name, parsedArgs)
 	if err != nil {
-		return transport.WrapError("get-prompt", err)
-	}
-
-	if resp.Error != nil {
-		return fmt.Errorf("server error: %v", resp.Error)
+		return interactiveError("get-prompt", err)
 	}
 
-	output, _ := json.MarshalIndent(resp.Result, "", "  ")
-	fmt.Printf("💬 Prompt:\n%s\n", string(output))
+	renderPrompt(result, s.Features())
 	return nil
 }
@@ -0,0 +1,329 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+// errInterrupted is returned by ReadLine when Ctrl-C discards the line
+var errInterrupted = errors.New("interrupted")
+
+// lineReader reads one line of REPL input. A read abandoned because ctx is
+// done returns ctx's error; the line typed so far is kept for the next read.
+type lineReader interface {
+	ReadLine(ctx context.Context, prompt string) (string, error)
+}
+
+// key is one rune of stdin, or the error that ended it
+type key struct {
+	r   rune
+	err error
+}
+
+// stdinKeys starts the one goroutine that reads stdin. Line readers take
+// their input from it, so they can stop waiting without losing a key.
+var stdinKeys = sync.OnceValue(func() <-chan key {
+	keys := make(chan key)
+	go func() {
+		in := bufio.NewReader(os.Stdin)
+		tty := isTerminal(os.Stdin)
+		for {
+			r, _, err := in.ReadRune()
+			keys <- key{r: r, err: err}
+
+			// Ctrl-D on a terminal ends one read, not the input
+			if err != nil && !(err == io.EOF && tty) {
+				for {
+					keys <- key{err: err}
+				}
+			}
+		}
+	}()
+	return keys
+})
+
+// nextKey waits for a key from stdin until ctx is done
+func nextKey(ctx context.Context) (rune, error) {
+	select {
+	case k := <-stdinKeys():
+		return k.r, k.err
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
+}
+
+// completer returns the candidates for the text before the cursor and the
+// offset in line where the replaced word starts
+type completer func(line string) (candidates []string, start int)
+
+// newLineReader returns a line editor with history and tab completion when
+// stdin is a terminal, and a plain line scanner otherwise
+func newLineReader(complete completer) lineReader {
+	out := diagnostics()
+	if isTerminal(os.Stdin) && isTerminal(out) {
+		if restore, err := makeRaw(os.Stdin); err == nil {
+			restore()
+			return &lineEditor{
+				out:      out,
+				complete: complete,
+			}
+		}
+	}
+	return &plainReader{}
+}
+
+// plainReader reads lines from stdin that isn't a terminal
+type plainReader struct {
+	line []rune
+}
+
+func (r *plainReader) ReadLine(ctx context.Context, prompt string) (string, error) {
+	fmt.Fprint(diagnostics(), prompt)
+
+	for {
+		c, err := nextKey(ctx)
+		if err == io.EOF && len(r.line) > 0 {
+			c, err = '\n', nil
+		}
+		if err != nil {
+			return "", err
+		}
+
+		if c == '\n' {
+			line := strings.TrimSuffix(string(r.line), "\r")
+			r.line = r.line[:0]
+			return line, nil
+		}
+		r.line = append(r.line, c)
+	}
+}
+
+// lineEditor is a small readline: cursor movement, history and tab
+// completion. The terminal is only in raw mode while a line is read, so
+// Ctrl-C still interrupts running commands.
+type lineEditor struct {
+	out      io.Writer
+	complete completer
+	history  []string
+
+	prompt string
+	buf    []rune
+	pos    int
+
+	// resume is set when a read was abandoned, so the next one picks up
+	// its line
+	resume bool
+}
+
+func (e *lineEditor) ReadLine(ctx context.Context, prompt string) (string, error) {
+	restore, err := makeRaw(os.Stdin)
+	if err != nil {
+		return "", err
+	}
+	defer restore()
+
+	e.prompt = prompt
+	if !e.resume {
+		e.buf = e.buf[:0]
+		e.pos = 0
+	}
+	e.resume = false
+	historyPos := len(e.history)
+	pending := ""
+
+	e.redraw()
+
+	for {
+		r, err := nextKey(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				// Clear the line for whatever is shown instead
+				fmt.Fprint(e.out, "\r\033[K")
+				e.resume = true
+			}
+			return "", err
+		}
+
+		switch r {
+		case '\r', '\n':
+			fmt.Fprint(e.out, "\n")
+			line := string(e.buf)
+			if strings.TrimSpace(line) != "" {
+				e.history = append(e.history, line)
+			}
+			return line, nil
+
+		case 3: // Ctrl-C
+			fmt.Fprint(e.out, "^C\n")
+			return "", errInterrupted
+
+		case 4: // Ctrl-D
+			if len(e.buf) == 0 {
+				fmt.Fprint(e.out, "\n")
+				return "", io.EOF
+			}
+			e.deleteAt(e.pos)
+
+		case '\t':
+			e.completeWord()
+
+		case 127, 8: // Backspace
+			if e.pos > 0 {
+				e.pos--
+				e.deleteAt(e.pos)
+			}
+
+		case 1: // Ctrl-A
+			e.pos = 0
+		case 5: // Ctrl-E
+			e.pos = len(e.buf)
+		case 2: // Ctrl-B
+			e.moveBy(-1)
+		case 6: // Ctrl-F
+			e.moveBy(1)
+
+		case 21: // Ctrl-U
+			e.buf = append(e.buf[:0], e.buf[e.pos:]...)
+			e.pos = 0
+
+		case 11: // Ctrl-K
+			e.buf = e.buf[:e.pos]
+
+		case 27: // Escape sequence
+			switch e.readEscape(ctx) {
+			case "[A", "OA":
+				if historyPos == len(e.history) {
+					pending = string(e.buf)
+				}
+				if historyPos > 0 {
+					historyPos--
+					e.setLine(e.history[historyPos])
+				}
+			case "[B", "OB":
+				if historyPos < len(e.history) {
+					historyPos++
+					if historyPos == len(e.history) {
+						e.setLine(pending)
+					} else {
+						e.setLine(e.history[historyPos])
+					}
+				}
+			case "[C", "OC":
+				e.moveBy(1)
+			case "[D", "OD":
+				e.moveBy(-1)
+			case "[H", "OH", "[1~":
+				e.pos = 0
+			case "[F", "OF", "[4~":
+				e.pos = len(e.buf)
+			case "[3~":
+				e.deleteAt(e.pos)
+			}
+
+		default:
+			if unicode.IsPrint(r) {
+				e.buf = append(e.buf[:e.pos], append([]rune{r}, e.buf[e.pos:]...)...)
+				e.pos++
+			}
+		}
+
+		e.redraw()
+	}
+}
+
+// readEscape reads the rest of an escape sequence such as "[A" or "[3~"
+func (e *lineEditor) readEscape(ctx context.Context) string {
+	first, err := nextKey(ctx)
+	if err != nil || (first != '[' && first != 'O') {
+		return ""
+	}
+
+	seq := []rune{first}
+	for {
+		r, err := nextKey(ctx)
+		if err != nil {
+			return ""
+		}
+		seq = append(seq, r)
+		// Final bytes of a CSI sequence are in the range @ to ~
+		if r >= '@' && r <= '~' {
+			return string(seq)
+		}
+	}
+}
+
+func (e *lineEditor) setLine(line string) {
+	e.buf = append(e.buf[:0], []rune(line)...)
+	e.pos = len(e.buf)
+}
+
+func (e *lineEditor) moveBy(n int) {
+	e.pos = min(max(e.pos+n, 0), len(e.buf))
+}
+
+func (e *lineEditor) deleteAt(i int) {
+	if i < len(e.buf) {
+		e.buf = append(e.buf[:i], e.buf[i+1:]...)
+	}
+}
+
+// redraw repaints the prompt and line and puts the cursor back in place
+func (e *lineEditor) redraw() {
+	fmt.Fprintf(e.out, "\r%s%s\033[K", e.prompt, string(e.buf))
+	if back := len(e.buf) - e.pos; back > 0 {
+		fmt.Fprintf(e.out, "\033[%dD", back)
+	}
+}
+
+// completeWord replaces the word before the cursor with its completion, or
+// with the candidates' common prefix and a listing when there are several
+func (e *lineEditor) completeWord() {
+	if e.complete == nil {
+		return
+	}
+
+	before := string(e.buf[:e.pos])
+	candidates, start := e.complete(before)
+	if len(candidates) == 0 {
+		fmt.Fprint(e.out, "\a")
+		return
+	}
+
+	word := before[start:]
+	replacement := commonPrefix(candidates)
+	if len(candidates) == 1 && !strings.HasSuffix(replacement, "=") && !strings.HasSuffix(replacement, "/") {
+		replacement += " "
+	}
+
+	if len(candidates) > 1 && len(replacement) <= len(word) {
+		fmt.Fprintf(e.out, "\n%s\n", strings.Join(candidates, "  "))
+		return
+	}
+
+	after := e.buf[e.pos:]
+	e.buf = append([]rune(before[:start]+replacement), after...)
+	e.pos = len([]rune(before[:start] + replacement))
+}
+
+func commonPrefix(values []string) string {
+	prefix := values[0]
+	for _, v := range values[1:] {
+		for !strings.HasPrefix(v, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+
+	// Don't cut a multi-byte character in half
+	for !utf8.ValidString(prefix) {
+		prefix = prefix[:len(prefix)-1]
+	}
+	return prefix
+}
@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/jkeresman01/mcp-client/client"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+var (
+	listCursor   string
+	listPageSize int
+	listNoFollow bool
+)
+
+// addPagingFlags registers the pagination flags shared by the list commands
+func addPagingFlags(cmd *cobra.Command) {
+	addPagingFlagSet(cmd.Flags(), &listCursor, &listPageSize, &listNoFollow)
+}
+
+func addPagingFlagSet(flags *pflag.FlagSet, cursor *string, pageSize *int, noFollow *bool) {
+	flags.StringVar(cursor, "cursor", "", "Start listing from a cursor returned by an earlier call")
+	flags.IntVar(pageSize, "page-size", 0, "Stop following nextCursor once at least N items were collected")
+	flags.BoolVar(noFollow, "no-follow", false, "Fetch a single page instead of following nextCursor")
+}
+
+func pageOptions() client.PageOptions {
+	return client.PageOptions{
+		Cursor:   listCursor,
+		NoFollow: listNoFollow,
+		Limit:    listPageSize,
+	}
+}
+
+// parsePagingArgs reads the paging flags given to an interactive list
+// command, e.g. "list-tools --no-follow --cursor abc"
+func parsePagingArgs(command string, args []string) (client.PageOptions, error) {
+	var opts client.PageOptions
+
+	flags := pflag.NewFlagSet(command, pflag.ContinueOnError)
+	flags.SetOutput(nopWriter{})
+	addPagingFlagSet(flags, &opts.Cursor, &opts.Limit, &opts.NoFollow)
+
+	if err := flags.Parse(args); err != nil {
+		return opts, fmt.Errorf("usage: %s [--cursor <cursor>] [--page-size <n>] [--no-follow]: %v", command, err)
+	}
+	return opts, nil
+}
+
+// listOutput rebuilds a list result in the shape the server sends
+func listOutput[T any](key string, result *client.ListResult[T]) map[string]interface{} {
+	out := map[string]interface{}{key: result.Items}
+	if result.NextCursor != "" {
+		out["nextCursor"] = result.NextCursor
+	}
+	return out
+}
+
+// printMoreHint tells the user how to fetch the pages we did not follow
+func printMoreHint[T any](result *client.ListResult[T]) {
+	if result.NextCursor != "" {
+		fmt.Printf("\nMore results available (%d items in %d page(s) so far). Continue with: --cursor %s\n",
+			len(result.Items), result.Pages, result.NextCursor)
+	}
+}
+
+type nopWriter struct{}
+
+func (nopWriter) Write(p []byte) (int, error) { return len(p), nil }
@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/jkeresman01/mcp-client/client"
+)
+
+const progressBarWidth = 30
+
+// progressRenderer draws notifications/progress updates for a running
+// request. On a terminal it redraws a single line; otherwise every update
+// is printed on its own line so logs stay readable.
+type progressRenderer struct {
+	mu     sync.Mutex
+	out    io.Writer
+	redraw bool
+	start  time.Time
+	drawn  bool
+	width  int
+}
+
+func newProgressRenderer(out *os.File) *progressRenderer {
+	return &progressRenderer{
+		out:    out,
+		redraw: isTerminal(out),
+		start:  time.Now(),
+	}
+}
+
+// Update renders one progress notification
+func (r *progressRenderer) Update(p client.Progress) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	line := formatProgress(p, time.Since(r.start))
+
+	if !r.redraw {
+		fmt.Fprintln(r.out, line)
+		return
+	}
+
+	// Pad with spaces so a shorter line fully covers the previous one
+	width := utf8.RuneCountInString(line)
+	padding := ""
+	if n := r.width - width; n > 0 {
+		padding = strings.Repeat(" ", n)
+	}
+	fmt.Fprintf(r.out, "\r%s%s", line, padding)
+
+	r.width = width
+	r.drawn = true
+}
+
+// Finish leaves the last progress line in place and moves to a new line
+func (r *progressRenderer) Finish() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.drawn {
+		fmt.Fprintln(r.out)
+		r.drawn = false
+	}
+}
+
+func formatProgress(p client.Progress, elapsed time.Duration) string {
+	var sb strings.Builder
+
+	if p.Total > 0 {
+		// Servers may send progress past the total or below zero
+		ratio := p.Progress / p.Total
+		if ratio > 1 {
+			ratio = 1
+		} else if ratio < 0 {
+			ratio = 0
+		}
+		filled := int(ratio * progressBarWidth)
+
+		sb.WriteString("[")
+		sb.WriteString(strings.Repeat("=", filled))
+		if filled < progressBarWidth {
+			sb.WriteString(">")
+			sb.WriteString(strings.Repeat(" ", progressBarWidth-filled-1))
+		}
+		sb.WriteString("]")
+		fmt.Fprintf(&sb, " %3.0f%% (%s/%s)", ratio*100, formatNumber(p.Progress), formatNumber(p.Total))
+	} else {
+		fmt.Fprintf(&sb, "Progress: %s", formatNumber(p.Progress))
+	}
+
+	if p.Message != "" {
+		sb.WriteString(" ")
+		sb.WriteString(p.Message)
+	}
+
+	fmt.Fprintf(&sb, " [%s]", elapsed.Round(100*time.Millisecond))
+	return sb.String()
+}
+
+// formatNumber prints whole numbers without a fractional part
+func formatNumber(f float64) string {
+	if f == float64(int64(f)) {
+		return fmt.Sprintf("%d", int64(f))
+	}
+	return fmt.Sprintf("%.2f", f)
+}
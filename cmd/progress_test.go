@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jkeresman01/mcp-client/client"
+)
+
+func TestFormatProgress(t *testing.T) {
+	bar := func(filled int) string {
+		if filled == progressBarWidth {
+			return "[" + strings.Repeat("=", filled) + "]"
+		}
+		return "[" + strings.Repeat("=", filled) + ">" + strings.Repeat(" ", progressBarWidth-filled-1) + "]"
+	}
+
+	tests := []struct {
+		name     string
+		progress client.Progress
+		want     string
+	}{
+		{"no total", client.Progress{Progress: 3}, "Progress: 3 [1.5s]"},
+		{"fractional", client.Progress{Progress: 0.25}, "Progress: 0.25 [1.5s]"},
+		{"message", client.Progress{Progress: 3, Message: "indexing"}, "Progress: 3 indexing [1.5s]"},
+		{"start", client.Progress{Progress: 0, Total: 10}, bar(0) + "   0% (0/10) [1.5s]"},
+		{"half", client.Progress{Progress: 5, Total: 10}, bar(15) + "  50% (5/10) [1.5s]"},
+		{"done", client.Progress{Progress: 10, Total: 10}, bar(30) + " 100% (10/10) [1.5s]"},
+		{"past the total", client.Progress{Progress: 12, Total: 10}, bar(30) + " 100% (12/10) [1.5s]"},
+		{"negative", client.Progress{Progress: -5, Total: 10}, bar(0) + "   0% (-5/10) [1.5s]"},
+	}
+
+	for _, tt := range tests {
+		if got := formatProgress(tt.progress, 1500*time.Millisecond); got != tt.want {
+			t.Errorf("%s: formatProgress() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestProgressPadsByRunes(t *testing.T) {
+	var out bytes.Buffer
+	r := &progressRenderer{out: &out, redraw: true, start: time.Now()}
+
+	r.Update(client.Progress{Progress: 1, Message: "ééééé"})
+	r.Update(client.Progress{Progress: 1, Message: "e"})
+
+	// The second line is four runes shorter, so four spaces cover the rest
+	lines := strings.Split(out.String(), "\r")
+	if got := lines[len(lines)-1]; !strings.HasSuffix(got, "]    ") || strings.HasSuffix(got, "]     ") {
+		t.Errorf("last line = %q, want it padded with four spaces", got)
+	}
+}
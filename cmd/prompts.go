@@ -2,9 +2,11 @@ package cmd
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
-	"github.com/jkeresman01/mcp-client/transport"
+	"strings"
 
+	"github.com/jkeresman01/mcp-client/transport"
 	"github.com/spf13/cobra"
 )
 
@@ -17,43 +19,38 @@ var (
 var listPromptsCmd = &cobra.Command{
 	Use:   "list-prompts",
 	Short: "List all available prompts",
-	Long:  `Retrieve and display all prompts available on the MCP server.`,
+	Long: `Retrieve and display all prompts available on the MCP server.
+Follows nextCursor until every page has been fetched unless --no-follow or
+--page-size is given.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		t, err := getTransport()
+		s, err := connect(cmd.Context())
 		if err != nil {
 			return err
 		}
-		defer t.Close()
+		defer s.Close()
 
-		req := transport.RPCRequest{
-			JSONRPC: "2.0",
-			ID:      20,
-			Method:  "prompts/list",
-			Params:  map[string]interface{}{},
-		}
-
-		if debugMode {
-			fmt.Println("Debug: Sending request:", req.Method)
-		}
+		ctx, cancel := requestContext(cmd.Context())
+		defer cancel()
 
-		resp, err := t.Send(req)
+		result, err := s.ListAllPrompts(ctx, pageOptions())
 		if err != nil {
-			return transport.WrapError("list-prompts", err)
-		}
-
-		if resp.Error != nil {
-			return &transport.MCPError{
-				Operation: "list-prompts",
-				Err:       fmt.Errorf("server returned error: %v", resp.Error),
-				Hints: []string{
-					"The server may not support prompts",
-					"Check server logs for more details",
-				},
+			var rpcErr *transport.RPCError
+			if errors.As(err, &rpcErr) {
+				return &transport.MCPError{
+					Operation: "list-prompts",
+					Err:       fmt.Errorf("server returned error: %v", rpcErr),
+					Hints: []string{
+						"The server may not support prompts",
+						"Check server logs for more details",
+					},
+				}
 			}
+			return transport.WrapError("list-prompts", err)
 		}
 
-		output, _ := json.MarshalIndent(resp.Result, "", "  ")
+		output, _ := json.MarshalIndent(listOutput("prompts", result), "", "  ")
 		fmt.Println("Prompts:\n", string(output))
+		printMoreHint(result)
 		return nil
 	},
 }
@@ -63,9 +60,14 @@ var getPromptCmd = &cobra.Command{
 	Short: "Get details of a specific prompt",
 	Long: `Retrieve details of a specific prompt from the MCP server using its name.
 
+Arguments are given as a JSON object or as comma-separated key=value pairs.
+Both prompt names and argument values can be tab-completed; values are
+suggested by the server via completion/complete.
+
 Examples:
   mcp-client get-prompt --name greeting
-  mcp-client get-prompt --name template --arguments '{"var":"value"}'`,
+  mcp-client get-prompt --name template --arguments '{"var":"value"}'
+  mcp-client get-prompt --name code_review --arguments language=go,style=terse`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if promptName == "" {
 			return &transport.MCPError{
@@ -78,75 +80,110 @@ Examples:
 			}
 		}
 
-		t, err := getTransport()
+		parsedArgs, err := parsePromptArguments(promptArguments)
 		if err != nil {
 			return err
 		}
-		defer t.Close()
-
-		params := map[string]interface{}{
-			"name": promptName,
-		}
-
-		if promptArguments != "" && promptArguments != "{}" {
-			var parsedArgs map[string]interface{}
-			if err := json.Unmarshal([]byte(promptArguments), &parsedArgs); err != nil {
-				return transport.NewInvalidArgumentsError(err.Error())
-			}
-			params["arguments"] = parsedArgs
-		}
 
-		req := transport.RPCRequest{
-			JSONRPC: "2.0",
-			ID:      21,
-			Method:  "prompts/get",
-			Params:  params,
+		s, err := connect(cmd.Context())
+		if err != nil {
+			return err
 		}
+		defer s.Close()
 
 		if debugMode {
 			fmt.Printf("Debug: Getting prompt '%s'\n", promptName)
 		}
 
-		resp, err := t.Send(req)
+		ctx, cancel := requestContext(cmd.Context())
+		defer cancel()
+
+		result, err := s.GetPrompt(ctx, promptName, parsedArgs)
 		if err != nil {
-			return transport.WrapError("get-prompt", err)
+			return promptError(promptName, err)
 		}
 
-		if resp.Error != nil {
-			errMap, ok := resp.Error.(map[string]interface{})
-			if ok {
-				if code, exists := errMap["code"]; exists && code == -32602 {
-					return &transport.MCPError{
-						Operation: "get-prompt",
-						Err:       fmt.Errorf("prompt '%s' not found", promptName),
-						Hints: []string{
-							"List available prompts: mcp-client list-prompts",
-							"Check if the prompt name is correct (case-sensitive)",
-						},
-					}
-				}
-			}
-			return &transport.MCPError{
-				Operation: "get-prompt",
-				Err:       fmt.Errorf("server error: %v", resp.Error),
+		renderPrompt(result, s.Features())
+		return nil
+	},
+}
+
+// parsePromptArguments reads --arguments, which is either a JSON object or
+// comma-separated key=value pairs
+func parsePromptArguments(raw string) (map[string]interface{}, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" || raw == "{}" {
+		return nil, nil
+	}
+
+	if strings.HasPrefix(raw, "{") {
+		var args map[string]interface{}
+		if err := json.Unmarshal([]byte(raw), &args); err != nil {
+			return nil, transport.NewInvalidArgumentsError(err.Error())
+		}
+		return args, nil
+	}
+
+	return parseKeyValuePairs(strings.Split(raw, ","))
+}
+
+// parseKeyValuePairs turns key=value pairs into prompt arguments
+func parseKeyValuePairs(pairs []string) (map[string]interface{}, error) {
+	args := map[string]interface{}{}
+	for _, pair := range pairs {
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok || key == "" {
+			return nil, &transport.MCPError{
+				Operation: "parsing arguments",
+				Err:       fmt.Errorf("invalid argument %q, expected key=value", pair),
 				Hints: []string{
-					"Verify the prompt name is correct",
-					"Check that all required arguments are provided",
-					"Check server logs for more details",
+					"Write arguments as key=value pairs, e.g. language=go,style=terse",
+					"Or as a JSON object: '{\"language\": \"go\"}'",
 				},
 			}
 		}
+		args[key] = value
+	}
+	return args, nil
+}
 
-		output, _ := json.MarshalIndent(resp.Result, "", "  ")
-		fmt.Println("Prompt Result:\n", string(output))
-		return nil
-	},
+// promptError explains a failed prompts/get
+func promptError(name string, err error) error {
+	var rpcErr *transport.RPCError
+	if !errors.As(err, &rpcErr) {
+		return transport.WrapError("get-prompt", err)
+	}
+
+	if rpcErr.Code == transport.CodeInvalidParams {
+		return &transport.MCPError{
+			Operation: "get-prompt",
+			Err:       fmt.Errorf("prompt '%s' not found", name),
+			Hints: []string{
+				"List available prompts: mcp-client list-prompts",
+				"Check if the prompt name is correct (case-sensitive)",
+			},
+		}
+	}
+
+	return &transport.MCPError{
+		Operation: "get-prompt",
+		Err:       fmt.Errorf("server error: %v", rpcErr),
+		Hints: []string{
+			"Verify the prompt name is correct",
+			"Check that all required arguments are provided",
+			"Check server logs for more details",
+		},
+	}
 }
 
 func init() {
 	getPromptCmd.Flags().StringVar(&promptName, "name", "", "Name of the prompt to get (required)")
-	getPromptCmd.Flags().StringVar(&promptArguments, "arguments", "{}", "JSON-encoded arguments to pass to the prompt")
+	getPromptCmd.Flags().StringVar(&promptArguments, "arguments", "{}", "Prompt arguments as a JSON object or key=value,key=value")
 	getPromptCmd.MarkFlagRequired("name")
+	getPromptCmd.RegisterFlagCompletionFunc("name", promptNameCompletion)
+	getPromptCmd.RegisterFlagCompletionFunc("arguments", promptArgumentsCompletion)
+
+	addPagingFlags(listPromptsCmd)
 
 	rootCmd.AddCommand(listPromptsCmd)
 	rootCmd.AddCommand(getPromptCmd)
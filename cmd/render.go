@@ -0,0 +1,303 @@
+package cmd
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/jkeresman01/mcp-client/client"
+)
+
+var (
+	rawOutput  bool
+	mediaDir   string
+	inlineMode string
+)
+
+// mediaCounter numbers saved files so names within a second don't clash
+var mediaCounter atomic.Int64
+
+// errToolFailed is returned when a tool result has isError set, so the
+// command exits non-zero
+type errToolFailed struct {
+	name string
+}
+
+func (e *errToolFailed) Error() string {
+	return fmt.Sprintf("tool '%s' reported an error", e.name)
+}
+
+// contentRenderer prints MCP content blocks for people: text as-is, images
+// inline or saved to files, audio saved to files, embedded resources
+// expanded and resource links listed at the end. Block types the
+// negotiated protocol version doesn't have are printed as JSON.
+type contentRenderer struct {
+	out      io.Writer
+	inline   string
+	features client.Features
+	links    []client.ContentBlock
+}
+
+func newContentRenderer(features client.Features) *contentRenderer {
+	return &contentRenderer{
+		out:      os.Stdout,
+		inline:   inlineProtocol(inlineMode, os.Stdout),
+		features: features,
+	}
+}
+
+// renderToolResult prints a tools/call result and reports isError as an
+// error. Structured content comes first, if the protocol version has it;
+// text blocks that only repeat it are left out.
+func renderToolResult(name string, result *client.CallToolResult, features client.Features) error {
+	if rawOutput {
+		out, _ := json.MarshalIndent(result, "", "  ")
+		fmt.Println("Tool Result:\n", string(out))
+	} else {
+		r := newContentRenderer(features)
+		blocks := result.Content
+		if result.StructuredContent != nil && features.StructuredOutput {
+			r.json(result.StructuredContent)
+
+			blocks = nil
+			for _, block := range result.Content {
+				if !duplicatesStructured(block, result.StructuredContent) {
+					blocks = append(blocks, block)
+				}
+			}
+			if len(blocks) > 0 {
+				fmt.Fprintln(r.out)
+			}
+		}
+		for _, block := range blocks {
+			r.block(block)
+		}
+		r.flushLinks()
+	}
+
+	if result.IsError {
+		return &errToolFailed{name: name}
+	}
+	return nil
+}
+
+// renderResource prints the contents of a resources/read result
+func renderResource(result *client.ReadResourceResult, features client.Features) {
+	if rawOutput {
+		output, _ := json.MarshalIndent(result, "", "  ")
+		fmt.Println("Resource content:\n", string(output))
+		return
+	}
+
+	r := newContentRenderer(features)
+	for _, content := range result.Contents {
+		r.resource(content, len(result.Contents) > 1)
+	}
+}
+
+// renderPrompt prints a prompts/get result as a transcript
+func renderPrompt(result *client.GetPromptResult, features client.Features) {
+	if rawOutput {
+		output, _ := json.MarshalIndent(result, "", "  ")
+		fmt.Println("Prompt Result:\n", string(output))
+		return
+	}
+
+	r := newContentRenderer(features)
+	if result.Description != "" {
+		fmt.Fprintf(r.out, "%s\n\n", result.Description)
+	}
+
+	for i, message := range result.Messages {
+		if i > 0 {
+			fmt.Fprintln(r.out)
+		}
+		fmt.Fprintf(r.out, "[%s]\n", message.Role)
+		r.block(message.Content)
+	}
+	r.flushLinks()
+}
+
+// block prints one content block
+func (r *contentRenderer) block(block client.ContentBlock) {
+	switch block.Type {
+	case client.ContentText:
+		fmt.Fprintln(r.out, strings.TrimSuffix(block.Text, "\n"))
+
+	case client.ContentImage:
+		r.media(block.Type, block.MimeType, block.Data)
+
+	case client.ContentAudio:
+		if !r.features.AudioContent {
+			r.json(block)
+			return
+		}
+		r.media(block.Type, block.MimeType, block.Data)
+
+	case client.ContentResource:
+		if block.Resource == nil {
+			r.json(block)
+			return
+		}
+		r.resource(*block.Resource, true)
+
+	case client.ContentResourceLink:
+		if !r.features.ResourceLinks {
+			r.json(block)
+			return
+		}
+		r.links = append(r.links, block)
+
+	default:
+		r.json(block)
+	}
+}
+
+// resource prints embedded or read resource contents, under a header with
+// its URI when header is set
+func (r *contentRenderer) resource(contents client.ResourceContents, header bool) {
+	if header {
+		if contents.MimeType != "" {
+			fmt.Fprintf(r.out, "--- %s (%s) ---\n", contents.URI, contents.MimeType)
+		} else {
+			fmt.Fprintf(r.out, "--- %s ---\n", contents.URI)
+		}
+	}
+
+	if contents.Blob == "" {
+		fmt.Fprintln(r.out, strings.TrimSuffix(contents.Text, "\n"))
+		return
+	}
+
+	kind := "resource"
+	if strings.HasPrefix(contents.MimeType, "image/") {
+		kind = "image"
+	} else if strings.HasPrefix(contents.MimeType, "audio/") {
+		kind = "audio"
+	}
+	r.media(kind, contents.MimeType, contents.Blob)
+}
+
+// media shows an image inline when the terminal supports it, and saves
+// anything else to --media-dir
+func (r *contentRenderer) media(kind, mimeType, data string) {
+	decoded, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		fmt.Fprintf(r.out, "[%s %s: invalid base64 data: %v]\n", kind, mimeType, err)
+		return
+	}
+
+	label := fmt.Sprintf("[%s %s, %s]", kind, mimeType, formatBytes(len(decoded)))
+
+	if kind == "image" && r.inline != "" {
+		if err := showInline(r.out, r.inline, mimeType, decoded); err == nil {
+			fmt.Fprintln(r.out, label)
+			return
+		}
+	}
+
+	path, err := saveMedia(kind, mimeType, decoded)
+	if err != nil {
+		fmt.Fprintf(r.out, "%s could not be saved: %v\n", label, err)
+		return
+	}
+	fmt.Fprintf(r.out, "%s saved to %s\n", label, path)
+}
+
+// flushLinks lists the resource_link blocks seen so far
+func (r *contentRenderer) flushLinks() {
+	if len(r.links) == 0 {
+		return
+	}
+
+	fmt.Fprintln(r.out, "Resource links:")
+	for _, link := range r.links {
+		line := "  " + link.URI
+		if link.Name != "" {
+			line += "  " + link.Name
+		}
+		if link.MimeType != "" {
+			line += " (" + link.MimeType + ")"
+		}
+		if link.Description != "" {
+			line += " - " + link.Description
+		}
+		fmt.Fprintln(r.out, line)
+	}
+	r.links = nil
+}
+
+func (r *contentRenderer) json(v interface{}) {
+	out, _ := json.MarshalIndent(v, "", "  ")
+	fmt.Fprintln(r.out, string(out))
+}
+
+// saveMedia writes decoded media to --media-dir, or a directory under the
+// system temp dir, naming the file after the kind and mime type
+func saveMedia(kind, mimeType string, data []byte) (string, error) {
+	dir := mediaDir
+	if dir == "" {
+		dir = filepath.Join(os.TempDir(), "mcp-client")
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+
+	name := fmt.Sprintf("%s-%s-%d%s", kind, time.Now().Format("20060102-150405"), mediaCounter.Add(1), extensionFor(mimeType))
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// commonExtensions are preferred over whatever the system mime table lists
+// first, e.g. .jpg rather than .jfif
+var commonExtensions = map[string]string{
+	"image/png":        ".png",
+	"image/jpeg":       ".jpg",
+	"image/gif":        ".gif",
+	"image/webp":       ".webp",
+	"image/svg+xml":    ".svg",
+	"audio/wav":        ".wav",
+	"audio/x-wav":      ".wav",
+	"audio/mpeg":       ".mp3",
+	"audio/ogg":        ".ogg",
+	"audio/webm":       ".webm",
+	"text/plain":       ".txt",
+	"text/markdown":    ".md",
+	"text/html":        ".html",
+	"application/json": ".json",
+	"application/pdf":  ".pdf",
+}
+
+// extensionFor returns a file extension for a mime type, or ".bin"
+func extensionFor(mimeType string) string {
+	base, _, _ := strings.Cut(mimeType, ";")
+	base = strings.TrimSpace(strings.ToLower(base))
+
+	if ext, ok := commonExtensions[base]; ok {
+		return ext
+	}
+	if exts, err := mime.ExtensionsByType(base); err == nil && len(exts) > 0 {
+		return exts[0]
+	}
+	return ".bin"
+}
+
+func formatBytes(n int) string {
+	switch {
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.1f KB", float64(n)/(1<<10))
+	}
+	return fmt.Sprintf("%d bytes", n)
+}
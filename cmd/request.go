@@ -0,0 +1,23 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"os/signal"
+)
+
+// requestContext returns the context a single MCP request runs under. It is
+// bounded by --timeout and cancelled by Ctrl-C, in which case the transport
+// sends notifications/cancelled for the in-flight request.
+func requestContext(parent context.Context) (context.Context, context.CancelFunc) {
+	ctx, stop := signal.NotifyContext(parent, os.Interrupt)
+	if requestTimeout <= 0 {
+		return ctx, stop
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	return ctx, func() {
+		cancel()
+		stop()
+	}
+}
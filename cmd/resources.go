@@ -2,55 +2,100 @@ package cmd
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
-	"github.com/jkeresman01/mcp-client/transport"
 
+	"github.com/jkeresman01/mcp-client/transport"
 	"github.com/spf13/cobra"
 )
 
-var resourceID string
+var (
+	resourceID       string
+	resourceTemplate string
+	templateVars     []string
+	resourceOutput   string
+	mirrorAll        bool
+	mirrorWorkers    int
+)
 
 var listResourcesCmd = &cobra.Command{
 	Use:   "list-resources",
 	Short: "List all available MCP resources",
-	Long:  `Retrieve and display all resources available on the MCP server.`,
+	Long: `Retrieve and display all resources available on the MCP server.
+Follows nextCursor until every page has been fetched unless --no-follow or
+--page-size is given.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		t, err := getTransport()
+		s, err := connect(cmd.Context())
 		if err != nil {
 			return err
 		}
-		defer t.Close()
+		defer s.Close()
 
-		req := transport.RPCRequest{
-			JSONRPC: "2.0",
-			ID:      10,
-			Method:  "resources/list",
-			Params:  map[string]interface{}{},
-		}
+		ctx, cancel := requestContext(cmd.Context())
+		defer cancel()
 
-		if debugMode {
-			fmt.Println("Debug: Sending request:", req.Method)
+		result, err := s.ListAllResources(ctx, pageOptions())
+		if err != nil {
+			var rpcErr *transport.RPCError
+			if errors.As(err, &rpcErr) {
+				return &transport.MCPError{
+					Operation: "list-resources",
+					Err:       fmt.Errorf("server returned error: %v", rpcErr),
+					Hints: []string{
+						"The server may not support resources",
+						"Run 'mcp-client init' to see the server's capabilities",
+						"Check server logs for more details",
+					},
+				}
+			}
+			return transport.WrapError("list-resources", err)
 		}
 
-		resp, err := t.Send(req)
+		output, _ := json.MarshalIndent(listOutput("resources", result), "", "  ")
+		fmt.Println("Resources:\n", string(output))
+		printMoreHint(result)
+		return nil
+	},
+}
+
+var listResourceTemplatesCmd = &cobra.Command{
+	Use:   "list-resource-templates",
+	Short: "List all available MCP resource templates",
+	Long: `Retrieve and display the resource templates of the MCP server, e.g.
+file:///{+path} or db://{table}/{id}. Read one with
+'mcp-client get-resource --template <uriTemplate> --var key=value'.
+Follows nextCursor until every page has been fetched unless --no-follow or
+--page-size is given.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		s, err := connect(cmd.Context())
 		if err != nil {
-			return transport.WrapError("list-resources", err)
+			return err
 		}
+		defer s.Close()
 
-		if resp.Error != nil {
-			return &transport.MCPError{
-				Operation: "list-resources",
-				Err:       fmt.Errorf("server returned error: %v", resp.Error),
-				Hints: []string{
-					"The server may not have initialized properly",
-					"Try running 'mcp-client init' first",
-					"Check server logs for more details",
-				},
+		ctx, cancel := requestContext(cmd.Context())
+		defer cancel()
+
+		result, err := s.ListAllResourceTemplates(ctx, pageOptions())
+		if err != nil {
+			var rpcErr *transport.RPCError
+			if errors.As(err, &rpcErr) {
+				return &transport.MCPError{
+					Operation: "list-resource-templates",
+					Err:       fmt.Errorf("server returned error: %v", rpcErr),
+					Hints: []string{
+						"The server may not support resource templates",
+						"Run 'mcp-client init' to see the server's capabilities",
+						"Check server logs for more details",
+					},
+				}
 			}
+			return transport.WrapError("list-resource-templates", err)
 		}
 
-		output, _ := json.MarshalIndent(resp.Result, "", "  ")
-		fmt.Println("Resources:\n", string(output))
+		output, _ := json.MarshalIndent(listOutput("resourceTemplates", result), "", "  ")
+		fmt.Println("Resource templates:\n", string(output))
+		printMoreHint(result)
 		return nil
 	},
 }
@@ -58,82 +103,161 @@ var listResourcesCmd = &cobra.Command{
 var getResourceCmd = &cobra.Command{
 	Use:   "get-resource",
 	Short: "Get content of a specific MCP resource by ID",
-	Long: `Retrieve the content of a specific resource from the MCP server using its ID.
+	Long: `Retrieve the content of a specific resource from the MCP server using its ID,
+or by expanding one of the server's resource templates (RFC 6570) with
+--template and --var.
+
+With --output the content is written to disk instead of printed: text as-is
+and blobs decoded. A directory target gets one file per content, with an
+extension picked from its mimeType. --all mirrors every listed resource into
+the --output directory as scheme/host/path, next to a manifest.json.
 
 Examples:
   mcp-client get-resource --id file:///path/to/file
-  mcp-client get-resource --id resource://my-resource --server prod`,
+  mcp-client get-resource --id resource://my-resource --server prod
+  mcp-client get-resource --template 'db://{table}/{id}' --var table=users --var id=42
+  mcp-client get-resource --template 'file:///{+path}' --var path=src/main.go
+  mcp-client get-resource --id file:///logo.png --output ./logo.png
+  mcp-client get-resource --id file:///docs/readme --output ./out/
+  mcp-client get-resource --all --output ./mirror --concurrency 8`,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if mirrorAll {
+			return runMirror(cmd)
+		}
+
+		if resourceTemplate != "" {
+			if resourceID != "" {
+				return &transport.MCPError{
+					Operation: "get-resource",
+					Err:       fmt.Errorf("--id and --template cannot be used together"),
+					Hints: []string{
+						"Read a concrete resource: --id <resource-uri>",
+						"Or expand a template: --template <uriTemplate> --var key=value",
+					},
+				}
+			}
+
+			uri, err := expandTemplate(resourceTemplate, templateVars)
+			if err != nil {
+				return err
+			}
+			resourceID = uri
+		}
+
 		if resourceID == "" {
 			return &transport.MCPError{
 				Operation: "get-resource",
 				Err:       fmt.Errorf("resource ID is required"),
 				Hints: []string{
 					"Specify resource URI: --id <resource-uri>",
+					"Or expand a template: --template <uriTemplate> --var key=value",
 					"List available resources: mcp-client list-resources",
 				},
 			}
 		}
 
-		t, err := getTransport()
+		s, err := connect(cmd.Context())
 		if err != nil {
 			return err
 		}
-		defer t.Close()
-
-		req := transport.RPCRequest{
-			JSONRPC: "2.0",
-			ID:      11,
-			Method:  "resources/read",
-			Params: map[string]interface{}{
-				"uri": resourceID,
-			},
-		}
+		defer s.Close()
 
 		if debugMode {
 			fmt.Printf("Debug: Reading resource: %s\n", resourceID)
 		}
 
-		resp, err := t.Send(req)
+		ctx, cancel := requestContext(cmd.Context())
+		defer cancel()
+
+		result, err := s.ReadResource(ctx, resourceID)
 		if err != nil {
-			return transport.WrapError("get-resource", err)
+			return resourceError(resourceID, err)
 		}
 
-		if resp.Error != nil {
-			errMap, ok := resp.Error.(map[string]interface{})
-			if ok {
-				if code, exists := errMap["code"]; exists && code == -32602 {
-					return &transport.MCPError{
-						Operation: "get-resource",
-						Err:       fmt.Errorf("resource '%s' not found", resourceID),
-						Hints: []string{
-							"List available resources: mcp-client list-resources",
-							"Check if the resource URI is correct",
-							"Verify you have permission to access this resource",
-						},
-					}
-				}
-			}
-			return &transport.MCPError{
-				Operation: "get-resource",
-				Err:       fmt.Errorf("server error: %v", resp.Error),
-				Hints: []string{
-					"Verify the resource URI is correct",
-					"Check server logs for more details",
-				},
-			}
+		if resourceOutput != "" {
+			return saveResource(result, resourceOutput)
 		}
-
-		output, _ := json.MarshalIndent(resp.Result, "", "  ")
-		fmt.Println("Resource content:\n", string(output))
+		renderResource(result, s.Features())
 		return nil
 	},
 }
 
+// runMirror handles get-resource --all
+func runMirror(cmd *cobra.Command) error {
+	if resourceOutput == "" {
+		return &transport.MCPError{
+			Operation: "get-resource",
+			Err:       fmt.Errorf("--all requires --output"),
+			Hints: []string{
+				"Choose a directory to mirror into: --all --output ./mirror",
+			},
+		}
+	}
+	if resourceID != "" || resourceTemplate != "" {
+		return &transport.MCPError{
+			Operation: "get-resource",
+			Err:       fmt.Errorf("--all cannot be combined with --id or --template"),
+			Hints: []string{
+				"Mirror everything: --all --output <dir>",
+				"Or save one resource: --id <resource-uri> --output <file|dir>",
+			},
+		}
+	}
+
+	s, err := connect(cmd.Context())
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+
+	cmd.SilenceUsage = true
+	return mirrorResources(cmd.Context(), s, resourceOutput, mirrorWorkers)
+}
+
+// resourceError explains a failed resources/read
+func resourceError(uri string, err error) error {
+	var rpcErr *transport.RPCError
+	if !errors.As(err, &rpcErr) {
+		return transport.WrapError("get-resource", err)
+	}
+
+	if rpcErr.Code == transport.CodeResourceNotFound || rpcErr.Code == transport.CodeInvalidParams {
+		return &transport.MCPError{
+			Operation: "get-resource",
+			Err:       fmt.Errorf("resource '%s' not found", uri),
+			Hints: []string{
+				"List available resources: mcp-client list-resources",
+				"Check if the resource URI is correct",
+				"Verify you have permission to access this resource",
+			},
+		}
+	}
+
+	return &transport.MCPError{
+		Operation: "get-resource",
+		Err:       fmt.Errorf("server error: %v", rpcErr),
+		Hints: []string{
+			"Verify the resource URI is correct",
+			"Check server logs for more details",
+		},
+	}
+}
+
 func init() {
 	getResourceCmd.Flags().StringVar(&resourceID, "id", "", "ID/URI of the resource to fetch")
-	getResourceCmd.MarkFlagRequired("id")
+	getResourceCmd.Flags().StringVar(&resourceTemplate, "template", "", "RFC 6570 URI template to expand instead of --id")
+	getResourceCmd.Flags().StringArrayVar(&templateVars, "var", nil, "Template variable as key=value (key[]=v for lists, key[name]=v for associative arrays)")
+	getResourceCmd.Flags().StringVarP(&resourceOutput, "output", "o", "", "Write the content to a file, or a directory (one file per content)")
+	getResourceCmd.Flags().BoolVar(&mirrorAll, "all", false, "Mirror every resource into the --output directory with a manifest")
+	getResourceCmd.Flags().IntVar(&mirrorWorkers, "concurrency", 4, "Resources read at once with --all")
+	getResourceCmd.RegisterFlagCompletionFunc("id", resourceURICompletion)
+	getResourceCmd.RegisterFlagCompletionFunc("template", templateURICompletion)
+	getResourceCmd.RegisterFlagCompletionFunc("var", templateVarCompletion)
+
+	addPagingFlags(listResourcesCmd)
+	addPagingFlags(listResourceTemplatesCmd)
 
 	rootCmd.AddCommand(listResourcesCmd)
+	rootCmd.AddCommand(listResourceTemplatesCmd)
 	rootCmd.AddCommand(getResourceCmd)
 }
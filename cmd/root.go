@@ -2,17 +2,25 @@ package cmd
 
 import (
 	"fmt"
+	"os"
+	"time"
+
 	"github.com/jkeresman01/mcp-client/config"
 	"github.com/spf13/cobra"
 )
 
 var (
-	serverURL     string
-	transportType string
-	commandPath   string
-	commandArgs   []string
-	serverName    string
-	debugMode     bool
+	serverURL      string
+	transportType  string
+	commandPath    string
+	commandArgs    []string
+	serverName     string
+	debugMode      bool
+	requestTimeout time.Duration
+	protoVersion   string
+	serverLogLevel string
+	serverLogFile  string
+	rootPaths      []string
 )
 
 var rootCmd = &cobra.Command{
@@ -31,36 +39,23 @@ Examples:
   mcp-client interactive --server local`,
 	PersistentPreRun: func(cmd *cobra.Command, args []string) {
 		// Don't print connection info for config commands or root help
-		if cmd.Parent() != nil && cmd.Parent().Name() == "config" {
+		if cmd.Parent() != nil && (cmd.Parent().Name() == "config" || cmd.Parent().Name() == "completion") {
 			return
 		}
 		if cmd.Name() == "mcp-client" || cmd.Name() == "config" {
 			return
 		}
+		// Shell completion reads stdout, completions apply the config themselves
+		if cmd.Name() == cobra.ShellCompRequestCmd {
+			return
+		}
 
-		// Load configuration if --server is specified
-		if serverName != "" {
-			cfg, err := config.Load(configFile)
-			if err != nil {
-				fmt.Printf("Warning: Could not load config: %v\n", err)
-				return
-			}
-
-			server, err := cfg.GetServer(serverName)
-			if err != nil {
-				fmt.Printf("Warning: %v\n", err)
-				return
-			}
-
-			// Override flags with config values
-			transportType = server.Transport
-			serverURL = server.URL
-			commandPath = server.Command
-			commandArgs = server.Args
-
-			if debugMode {
-				fmt.Printf("Debug: Using server '%s' from config\n", serverName)
-			}
+		if err := applyServerConfig(cmd); err != nil {
+			fmt.Fprintf(diagnostics(), "Warning: %v\n", err)
+		}
+		// Only the structured JSON goes to stdout, so it can be piped
+		if structuredOnly {
+			return
 		}
 
 		// Print connection info
@@ -74,12 +69,65 @@ Examples:
 			fmt.Printf("Command: %s %v\n", commandPath, commandArgs)
 		}
 		if debugMode {
+			fmt.Printf("Timeout: %v\n", requestTimeout)
 			fmt.Println("--------------------------------------------------------")
 		}
 	},
 }
 
+// applyServerConfig overrides the connection flags with the server selected
+// by --server, if any
+func applyServerConfig(cmd *cobra.Command) error {
+	if serverName == "" {
+		return nil
+	}
+
+	cfg, err := config.Load(configFile)
+	if err != nil {
+		return fmt.Errorf("Could not load config: %v", err)
+	}
+
+	server, err := cfg.GetServer(serverName)
+	if err != nil {
+		return err
+	}
+
+	// Override flags with config values
+	transportType = server.Transport
+	serverURL = server.URL
+	commandPath = server.Command
+	commandArgs = server.Args
+
+	if server.ProtocolVersion != "" && !cmd.Flags().Changed("protocol-version") {
+		protoVersion = server.ProtocolVersion
+	}
+
+	if len(server.Roots) > 0 && !cmd.Flags().Changed("root") {
+		rootPaths = server.Roots
+	}
+	if server.Sampling != nil && !samplingFlagsChanged(cmd) {
+		samplingConfig = *server.Sampling
+	}
+
+	if debugMode {
+		fmt.Fprintf(diagnostics(), "Debug: Using server '%s' from config\n", serverName)
+	}
+
+	// An explicit --timeout wins over the per-server timeout, which can
+	// be "0" to disable it like --timeout 0
+	if server.Timeout != "" && !cmd.Flags().Changed("timeout") {
+		timeout, err := server.RequestTimeout()
+		if err != nil {
+			return err
+		}
+		requestTimeout = timeout
+	}
+
+	return nil
+}
+
 func Execute() {
+	addToolCommands(os.Args[1:])
 	cobra.CheckErr(rootCmd.Execute())
 }
 
@@ -91,4 +139,19 @@ func init() {
 	rootCmd.PersistentFlags().StringVar(&serverName, "server", "", "Use a named server from config file")
 	rootCmd.PersistentFlags().StringVar(&configFile, "config", "", "Config file path (default: ~/.mcp-config.json)")
 	rootCmd.PersistentFlags().BoolVar(&debugMode, "debug", false, "Enable debug mode with verbose output")
+	rootCmd.PersistentFlags().StringVar(&protoVersion, "protocol-version", "", "Pin the MCP protocol version (default: negotiate, newest first)")
+	rootCmd.PersistentFlags().DurationVar(&requestTimeout, "timeout", 60*time.Second, "Timeout for each request to the server (0 disables)")
+	rootCmd.PersistentFlags().StringVar(&serverLogLevel, "server-log-level", "", "Ask the server to send logs at this level and above (debug, info, notice, warning, error, ...)")
+	rootCmd.PersistentFlags().StringVar(&serverLogFile, "server-log-file", "", "Also append server log messages to this file as JSONL")
+	rootCmd.PersistentFlags().StringArrayVar(&rootPaths, "root", nil, "Directory or URI to expose to the server as a root (repeatable, default: current directory)")
+	rootCmd.PersistentFlags().StringVar(&samplingConfig.Command, "sampling-command", "", "Answer sampling requests with this command (request JSON on stdin, result on stdout)")
+	rootCmd.PersistentFlags().StringVar(&samplingConfig.Fixture, "sampling-fixture", "", "Answer sampling requests from a JSON file of scripted responses")
+	rootCmd.PersistentFlags().StringVar(&samplingConfig.BaseURL, "sampling-url", "", "Answer sampling requests with an OpenAI-compatible API at this base URL")
+	rootCmd.PersistentFlags().StringVar(&samplingConfig.Model, "sampling-model", "", "Model for --sampling-url (default: the server's first model hint)")
+	rootCmd.PersistentFlags().BoolVar(&samplingConfig.AutoApprove, "sampling-auto-approve", false, "Don't ask before answering sampling requests in interactive mode")
+	rootCmd.PersistentFlags().BoolVar(&rawOutput, "raw", false, "Print tool, resource and prompt results as raw JSON")
+	rootCmd.PersistentFlags().StringVar(&mediaDir, "media-dir", "", "Directory for images and audio from results (default: mcp-client under the temp dir)")
+	rootCmd.PersistentFlags().StringVar(&inlineMode, "inline", "auto", "Show images inline: auto | kitty | iterm | sixel | none")
+	rootCmd.PersistentFlags().StringVar(&elicitAnswersFile, "elicit-answers", "", "Answer elicitation requests from a JSON object of field values instead of asking")
+	rootCmd.PersistentFlags().StringArrayVar(&elicitAnswers, "elicit", nil, "Answer an elicitation field without asking, as key=value (repeatable)")
 }
@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/jkeresman01/mcp-client/client"
+)
+
+// parseRoot turns a --root value into a root. Paths are made absolute and
+// converted to file:// URIs; anything with a scheme is used as-is.
+func parseRoot(value string) (client.Root, error) {
+	if value == "" {
+		return client.Root{}, fmt.Errorf("empty root")
+	}
+
+	if u, err := url.Parse(value); err == nil && len(u.Scheme) > 1 && strings.Contains(value, "://") {
+		return client.Root{URI: value, Name: path.Base(u.Path)}, nil
+	}
+
+	abs, err := filepath.Abs(value)
+	if err != nil {
+		return client.Root{}, fmt.Errorf("invalid root %q: %v", value, err)
+	}
+
+	// Windows paths become file:///C:/...
+	p := filepath.ToSlash(abs)
+	if !strings.HasPrefix(p, "/") {
+		p = "/" + p
+	}
+	u := url.URL{Scheme: "file", Path: p}
+	return client.Root{URI: u.String(), Name: filepath.Base(abs)}, nil
+}
+
+// configuredRoots returns the roots from --root or the server config,
+// falling back to the current directory
+func configuredRoots() ([]client.Root, error) {
+	values := rootPaths
+	if len(values) == 0 {
+		cwd, err := os.Getwd()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get current directory: %v", err)
+		}
+		values = []string{cwd}
+	}
+
+	roots := make([]client.Root, 0, len(values))
+	for _, v := range values {
+		root, err := parseRoot(v)
+		if err != nil {
+			return nil, err
+		}
+		roots = append(roots, root)
+	}
+	return roots, nil
+}
+
+// rootsInteractive handles "roots", "roots add <path>" and
+// "roots remove <path-or-uri>". Changes are announced to the server with
+// notifications/roots/list_changed.
+func rootsInteractive(s *client.Session, args []string) error {
+	if len(args) == 0 || args[0] == "list" {
+		roots := s.Roots()
+		if len(roots) == 0 {
+			fmt.Println("No roots.")
+			return nil
+		}
+		fmt.Println("Roots:")
+		for _, r := range roots {
+			if r.Name != "" {
+				fmt.Printf("  %s (%s)\n", r.URI, r.Name)
+			} else {
+				fmt.Printf("  %s\n", r.URI)
+			}
+		}
+		return nil
+	}
+
+	if len(args) < 2 || (args[0] != "add" && args[0] != "remove") {
+		return fmt.Errorf("usage: roots [list]\n       roots add <path-or-uri>\n       roots remove <path-or-uri>")
+	}
+
+	root, err := parseRoot(args[1])
+	if err != nil {
+		return err
+	}
+
+	roots := s.Roots()
+	index := -1
+	for i, r := range roots {
+		if r.URI == root.URI || r.URI == args[1] {
+			index = i
+			break
+		}
+	}
+
+	if args[0] == "add" {
+		if index >= 0 {
+			return fmt.Errorf("%s is already a root", root.URI)
+		}
+		roots = append(roots, root)
+	} else {
+		if index < 0 {
+			return fmt.Errorf("%s is not a root", root.URI)
+		}
+		roots = append(roots[:index], roots[index+1:]...)
+	}
+
+	ctx, cancel := requestContext(context.Background())
+	defer cancel()
+
+	if err := s.SetRoots(ctx, roots); err != nil {
+		return interactiveError("roots", err)
+	}
+
+	if args[0] == "add" {
+		fmt.Printf("Added root %s\n", root.URI)
+	} else {
+		fmt.Printf("Removed root %s\n", root.URI)
+	}
+	return nil
+}
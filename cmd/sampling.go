@@ -0,0 +1,425 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/jkeresman01/mcp-client/client"
+	"github.com/jkeresman01/mcp-client/config"
+	"github.com/jkeresman01/mcp-client/transport"
+	"github.com/spf13/cobra"
+)
+
+// samplingConfig holds the --sampling-* flags, or the sampling block of the
+// server selected with --server
+var samplingConfig config.SamplingConfig
+
+// samplingFlags are the flags that fill samplingConfig
+var samplingFlags = []string{
+	"sampling-command", "sampling-fixture", "sampling-url", "sampling-model", "sampling-auto-approve",
+}
+
+// samplingFlagsChanged reports whether any --sampling-* flag was given, in
+// which case the server's sampling block is ignored
+func samplingFlagsChanged(cmd *cobra.Command) bool {
+	for _, name := range samplingFlags {
+		if cmd.Flags().Changed(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// errSamplingRejected is sent to the server when the user says no
+var errSamplingRejected = &transport.RPCError{
+	Code:    -1,
+	Message: "User rejected sampling request",
+}
+
+// installSampling answers sampling/createMessage with the configured
+// handler. Without one the sampling capability is not advertised.
+func installSampling(s *client.Session) error {
+	sampler, name, err := newSampler(samplingConfig)
+	if err != nil {
+		return &transport.MCPError{
+			Operation: "configuring sampling",
+			Err:       err,
+			Hints: []string{
+				"Use one of --sampling-command, --sampling-fixture or --sampling-url",
+				"Example: --sampling-url http://localhost:11434/v1 --sampling-model llama3.2",
+			},
+		}
+	}
+	if sampler == nil {
+		return nil
+	}
+
+	approve := !samplingConfig.AutoApprove
+	s.SetSamplingHandler(func(ctx context.Context, req *client.CreateMessageRequest) (*client.CreateMessageResult, error) {
+		result, err := sample(ctx, req, sampler, name, approve)
+		if err != nil && err != errSamplingRejected {
+			fmt.Fprintf(diagnostics(), "Warning: sampling request failed: %v\n", err)
+		}
+		return result, err
+	})
+	return nil
+}
+
+// sample runs the handler. In interactive mode the user approves both the
+// request and the response before anything reaches the server.
+func sample(ctx context.Context, req *client.CreateMessageRequest, sampler client.SamplingHandler, name string, approve bool) (*client.CreateMessageResult, error) {
+	if !approve || !interactiveMode {
+		return sampler(ctx, req)
+	}
+
+	if err := userInput.ask(ctx); err != nil {
+		return nil, err
+	}
+	defer userInput.done()
+
+	fmt.Fprintln(diagnostics())
+	fmt.Fprintln(diagnostics(), "The server requests an LLM completion:")
+	if req.SystemPrompt != "" {
+		fmt.Fprintf(diagnostics(), "  [system] %s\n", req.SystemPrompt)
+	}
+	for _, m := range req.Messages {
+		fmt.Fprintf(diagnostics(), "  [%s] %s\n", m.Role, contentSummary(m.Content))
+	}
+	fmt.Fprintf(diagnostics(), "  (max %d tokens)\n", req.MaxTokens)
+
+	if !confirm(ctx, fmt.Sprintf("Send it to %s?", name)) {
+		return nil, errSamplingRejected
+	}
+
+	result, err := sampler(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	fmt.Fprintf(diagnostics(), "  [%s, %s] %s\n", result.Role, result.Model, contentSummary(result.Content))
+	if !confirm(ctx, "Return this response to the server?") {
+		return nil, errSamplingRejected
+	}
+	return result, nil
+}
+
+// describeSampling names the configured handler for config list
+func describeSampling(cfg config.SamplingConfig) string {
+	switch {
+	case cfg.Command != "":
+		return "command " + cfg.Command
+	case cfg.Fixture != "":
+		return "fixture " + cfg.Fixture
+	case cfg.BaseURL != "" && cfg.Model != "":
+		return cfg.BaseURL + " (" + cfg.Model + ")"
+	case cfg.BaseURL != "":
+		return cfg.BaseURL
+	}
+	return "none"
+}
+
+// newSampler builds the handler selected by cfg, and a name for it to show
+// in approval prompts. It returns nil if no handler is configured.
+func newSampler(cfg config.SamplingConfig) (client.SamplingHandler, string, error) {
+	set := 0
+	for _, v := range []string{cfg.Command, cfg.Fixture, cfg.BaseURL} {
+		if v != "" {
+			set++
+		}
+	}
+	if set > 1 {
+		return nil, "", fmt.Errorf("only one sampling handler can be configured")
+	}
+
+	switch {
+	case cfg.Command != "":
+		fields, err := splitCommand(cfg.Command)
+		if err != nil {
+			return nil, "", fmt.Errorf("invalid sampling command %q: %v", cfg.Command, err)
+		}
+		if len(fields) == 0 {
+			return nil, "", fmt.Errorf("empty sampling command")
+		}
+		return execSampler(fields), fields[0], nil
+
+	case cfg.Fixture != "":
+		fixtures, err := loadSamplingFixtures(cfg.Fixture)
+		if err != nil {
+			return nil, "", err
+		}
+		return fixtureSampler(fixtures), cfg.Fixture, nil
+
+	case cfg.BaseURL != "":
+		return openAISampler(cfg), cfg.BaseURL, nil
+	}
+
+	return nil, "", nil
+}
+
+// execSampler runs a command per request, writing the request JSON to its
+// stdin. The command prints a CreateMessageResult, or just the text of the
+// reply.
+func execSampler(command []string) client.SamplingHandler {
+	return func(ctx context.Context, req *client.CreateMessageRequest) (*client.CreateMessageResult, error) {
+		input, err := json.Marshal(req)
+		if err != nil {
+			return nil, err
+		}
+
+		var stderr bytes.Buffer
+		c := exec.CommandContext(ctx, command[0], command[1:]...)
+		c.Stdin = bytes.NewReader(input)
+		c.Stderr = &stderr
+
+		out, err := c.Output()
+		if err != nil {
+			if msg := strings.TrimSpace(stderr.String()); msg != "" {
+				return nil, fmt.Errorf("%s: %v: %s", command[0], err, msg)
+			}
+			return nil, fmt.Errorf("%s: %v", command[0], err)
+		}
+
+		out = bytes.TrimSpace(out)
+		if !bytes.HasPrefix(out, []byte("{")) {
+			return textResult(string(out), command[0]), nil
+		}
+
+		var result client.CreateMessageResult
+		if err := json.Unmarshal(out, &result); err != nil {
+			return nil, fmt.Errorf("%s printed an invalid result: %v", command[0], err)
+		}
+		if result.Content == nil {
+			return nil, fmt.Errorf("%s printed a result without content", command[0])
+		}
+		if result.Role == "" {
+			result.Role = "assistant"
+		}
+		if result.Model == "" {
+			result.Model = command[0]
+		}
+		return &result, nil
+	}
+}
+
+// samplingFixture is one scripted response. The first entry whose Match
+// occurs in the text of the request answers it; an empty Match answers
+// anything. Text is shorthand for a plain text Response.
+type samplingFixture struct {
+	Match    string                      `json:"match,omitempty"`
+	Text     string                      `json:"text,omitempty"`
+	Response *client.CreateMessageResult `json:"response,omitempty"`
+}
+
+func loadSamplingFixtures(path string) ([]samplingFixture, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read sampling fixture: %v", err)
+	}
+
+	var fixtures []samplingFixture
+	if err := json.Unmarshal(data, &fixtures); err != nil {
+		return nil, fmt.Errorf("failed to parse sampling fixture %s: %v", path, err)
+	}
+
+	for i, f := range fixtures {
+		if f.Text == "" && f.Response == nil {
+			return nil, fmt.Errorf("sampling fixture %s: entry %d has neither text nor response", path, i)
+		}
+	}
+	return fixtures, nil
+}
+
+func fixtureSampler(fixtures []samplingFixture) client.SamplingHandler {
+	return func(ctx context.Context, req *client.CreateMessageRequest) (*client.CreateMessageResult, error) {
+		text := requestText(req)
+
+		for _, f := range fixtures {
+			if !strings.Contains(text, f.Match) {
+				continue
+			}
+			if f.Response != nil {
+				return f.Response, nil
+			}
+			return textResult(f.Text, "fixture"), nil
+		}
+
+		return nil, &transport.RPCError{
+			Code:    transport.CodeInternalError,
+			Message: "No scripted response matches the request",
+		}
+	}
+}
+
+// requestText joins the system prompt and the text of every message
+func requestText(req *client.CreateMessageRequest) string {
+	parts := []string{req.SystemPrompt}
+	for _, m := range req.Messages {
+		if text, ok := m.Content["text"].(string); ok {
+			parts = append(parts, text)
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+// openAISampler forwards requests to an OpenAI-compatible chat completions
+// API, such as a local llama.cpp server or Ollama
+func openAISampler(cfg config.SamplingConfig) client.SamplingHandler {
+	endpoint := strings.TrimSuffix(cfg.BaseURL, "/") + "/chat/completions"
+
+	keyEnv := cfg.APIKeyEnv
+	if keyEnv == "" {
+		keyEnv = "OPENAI_API_KEY"
+	}
+
+	return func(ctx context.Context, req *client.CreateMessageRequest) (*client.CreateMessageResult, error) {
+		model := cfg.Model
+		if model == "" && req.ModelPreferences != nil && len(req.ModelPreferences.Hints) > 0 {
+			model = req.ModelPreferences.Hints[0].Name
+		}
+		if model == "" {
+			return nil, fmt.Errorf("no model to send to %s: set --sampling-model", cfg.BaseURL)
+		}
+
+		var messages []map[string]interface{}
+		if req.SystemPrompt != "" {
+			messages = append(messages, map[string]interface{}{"role": "system", "content": req.SystemPrompt})
+		}
+		for _, m := range req.Messages {
+			content, err := openAIContent(m.Content)
+			if err != nil {
+				return nil, err
+			}
+			messages = append(messages, map[string]interface{}{"role": m.Role, "content": content})
+		}
+
+		body := map[string]interface{}{
+			"model":    model,
+			"messages": messages,
+		}
+		if req.MaxTokens > 0 {
+			body["max_tokens"] = req.MaxTokens
+		}
+		if req.Temperature != nil {
+			body["temperature"] = *req.Temperature
+		}
+		if len(req.StopSequences) > 0 {
+			body["stop"] = req.StopSequences
+		}
+
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+
+		httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		httpReq.Header.Set("Content-Type", "application/json")
+		if key := os.Getenv(keyEnv); key != "" {
+			httpReq.Header.Set("Authorization", "Bearer "+key)
+		}
+
+		resp, err := http.DefaultClient.Do(httpReq)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+
+		respBody, _ := io.ReadAll(resp.Body)
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("%s returned HTTP %d: %s", endpoint, resp.StatusCode, bytes.TrimSpace(respBody))
+		}
+
+		var completion struct {
+			Model   string `json:"model"`
+			Choices []struct {
+				Message struct {
+					Content string `json:"content"`
+				} `json:"message"`
+				FinishReason string `json:"finish_reason"`
+			} `json:"choices"`
+		}
+		if err := json.Unmarshal(respBody, &completion); err != nil {
+			return nil, fmt.Errorf("failed to parse response from %s: %v", endpoint, err)
+		}
+		if len(completion.Choices) == 0 {
+			return nil, fmt.Errorf("%s returned no choices", endpoint)
+		}
+
+		choice := completion.Choices[0]
+		if completion.Model == "" {
+			completion.Model = model
+		}
+
+		result := textResult(choice.Message.Content, completion.Model)
+		switch choice.FinishReason {
+		case "stop":
+			result.StopReason = "endTurn"
+		case "length":
+			result.StopReason = "maxTokens"
+		default:
+			result.StopReason = choice.FinishReason
+		}
+		return result, nil
+	}
+}
+
+// openAIContent converts an MCP content block to chat completions content
+func openAIContent(c map[string]interface{}) (interface{}, error) {
+	mimeType, _ := c["mimeType"].(string)
+	data, _ := c["data"].(string)
+
+	switch c["type"] {
+	case "text":
+		return c["text"], nil
+
+	case "image":
+		return []interface{}{map[string]interface{}{
+			"type":      "image_url",
+			"image_url": map[string]interface{}{"url": "data:" + mimeType + ";base64," + data},
+		}}, nil
+
+	case "audio":
+		format := strings.TrimPrefix(mimeType, "audio/")
+		if format == "mpeg" {
+			format = "mp3"
+		}
+		return []interface{}{map[string]interface{}{
+			"type":        "input_audio",
+			"input_audio": map[string]interface{}{"data": data, "format": format},
+		}}, nil
+	}
+
+	return nil, &transport.RPCError{
+		Code:    transport.CodeInvalidParams,
+		Message: fmt.Sprintf("Unsupported content type for sampling: %v", c["type"]),
+	}
+}
+
+func textResult(text, model string) *client.CreateMessageResult {
+	return &client.CreateMessageResult{
+		Role:       "assistant",
+		Content:    map[string]interface{}{"type": "text", "text": text},
+		Model:      model,
+		StopReason: "endTurn",
+	}
+}
+
+// contentSummary is a one-line description of a content block
+func contentSummary(c map[string]interface{}) string {
+	if text, ok := c["text"].(string); ok {
+		return text
+	}
+
+	mimeType, _ := c["mimeType"].(string)
+	data, _ := c["data"].(string)
+	return fmt.Sprintf("[%v %s, %d bytes base64]", c["type"], mimeType, len(data))
+}
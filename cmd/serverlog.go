@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jkeresman01/mcp-client/client"
+	"github.com/jkeresman01/mcp-client/transport"
+)
+
+// ANSI colors for server log levels
+var logLevelColors = map[string]string{
+	"debug":     "\033[90m",
+	"info":      "\033[36m",
+	"notice":    "\033[32m",
+	"warning":   "\033[33m",
+	"error":     "\033[31m",
+	"critical":  "\033[1;31m",
+	"alert":     "\033[1;31m",
+	"emergency": "\033[1;41;97m",
+}
+
+const colorReset = "\033[0m"
+
+// serverLog prints notifications/message to stderr and optionally appends
+// them to a JSONL file
+type serverLog struct {
+	mu    sync.Mutex
+	out   io.Writer
+	color bool
+	file  *os.File
+}
+
+// serverLogEntry is one line of --server-log-file
+type serverLogEntry struct {
+	Time   time.Time       `json:"time"`
+	Level  string          `json:"level"`
+	Logger string          `json:"logger,omitempty"`
+	Data   json.RawMessage `json:"data"`
+}
+
+// installServerLog validates the server log flags and starts printing the
+// session's log messages
+func installServerLog(s *client.Session) error {
+	if serverLogLevel != "" && !client.IsLoggingLevel(serverLogLevel) {
+		return &transport.MCPError{
+			Operation: "configuring server logging",
+			Err:       fmt.Errorf("unknown log level: %s", serverLogLevel),
+			Hints: []string{
+				fmt.Sprintf("Valid levels are: %s", strings.Join(client.LoggingLevels, ", ")),
+				"Example: --server-log-level debug",
+			},
+		}
+	}
+
+	l := &serverLog{
+		out:   os.Stderr,
+		color: isTerminal(os.Stderr) && os.Getenv("NO_COLOR") == "",
+	}
+
+	if serverLogFile != "" {
+		f, err := os.OpenFile(serverLogFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			return &transport.MCPError{
+				Operation: "opening server log file",
+				Err:       err,
+				Hints: []string{
+					"Check that the directory exists and is writable",
+				},
+			}
+		}
+		l.file = f
+	}
+
+	s.OnLogMessage(l.write)
+	return nil
+}
+
+// applyServerLogLevel sends logging/setLevel if --server-log-level was given.
+// Failures are reported but don't stop the command.
+func applyServerLogLevel(parent context.Context, s *client.Session) {
+	if serverLogLevel == "" {
+		return
+	}
+
+	if !s.HasCapability("logging") {
+		fmt.Fprintf(diagnostics(), "Warning: server does not advertise the logging capability, --server-log-level ignored\n")
+		return
+	}
+
+	ctx, cancel := requestContext(parent)
+	defer cancel()
+
+	if err := s.SetLoggingLevel(ctx, serverLogLevel); err != nil {
+		fmt.Fprintf(diagnostics(), "Warning: failed to set server log level: %v\n", err)
+		return
+	}
+
+	if debugMode {
+		fmt.Fprintf(diagnostics(), "Debug: Server log level set to %s\n", serverLogLevel)
+	}
+}
+
+func (l *serverLog) write(msg client.LogMessage) {
+	now := time.Now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	level := fmt.Sprintf("%-9s", strings.ToUpper(msg.Level))
+	if l.color {
+		if c, ok := logLevelColors[msg.Level]; ok {
+			level = c + level + colorReset
+		}
+	}
+
+	logger := ""
+	if msg.Logger != "" {
+		logger = "[" + msg.Logger + "] "
+	}
+
+	fmt.Fprintf(l.out, "%s %s %s%s\n", now.Format("15:04:05.000"), level, logger, formatLogData(msg.Data))
+
+	if l.file != nil {
+		entry := serverLogEntry{
+			Time:   now,
+			Level:  msg.Level,
+			Logger: msg.Logger,
+			Data:   msg.Data,
+		}
+		if data, err := json.Marshal(entry); err == nil {
+			l.file.Write(append(data, '\n'))
+		}
+	}
+}
+
+// formatLogData prints string data as-is and anything else as compact JSON
+func formatLogData(data json.RawMessage) string {
+	var s string
+	if json.Unmarshal(data, &s) == nil {
+		return s
+	}
+
+	var buf bytes.Buffer
+	if json.Compact(&buf, data) != nil {
+		return string(data)
+	}
+	return buf.String()
+}
@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+)
+
+// splitCommand splits a command line into arguments the way a POSIX shell
+// does, without expanding anything: single quotes keep everything, double
+// quotes keep everything but \", \\, \$ and \`, and a backslash outside
+// quotes escapes the next character
+func splitCommand(line string) ([]string, error) {
+	var args []string
+	var current strings.Builder
+	inWord := false
+	var quote rune
+	escaped := false
+
+	for _, c := range line {
+		switch {
+		case escaped:
+			if quote == '"' && !strings.ContainsRune("\"\\$`", c) {
+				current.WriteRune('\\')
+			}
+			current.WriteRune(c)
+			escaped = false
+
+		case c == '\\' && quote != '\'':
+			escaped = true
+			inWord = true
+
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			} else {
+				current.WriteRune(c)
+			}
+
+		case c == '\'' || c == '"':
+			quote = c
+			inWord = true
+
+		case c == ' ' || c == '\t' || c == '\n':
+			if inWord {
+				args = append(args, current.String())
+				current.Reset()
+				inWord = false
+			}
+
+		default:
+			current.WriteRune(c)
+			inWord = true
+		}
+	}
+
+	if escaped {
+		return nil, fmt.Errorf("command ends with a backslash")
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote", quote)
+	}
+	if inWord {
+		args = append(args, current.String())
+	}
+	return args, nil
+}
@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitCommand(t *testing.T) {
+	tests := []struct {
+		line string
+		want []string
+	}{
+		{`python3 sampler.py`, []string{"python3", "sampler.py"}},
+		{`  llm   --model  x  `, []string{"llm", "--model", "x"}},
+		{`"/Applications/My Tool/llm" --model "x y"`, []string{"/Applications/My Tool/llm", "--model", "x y"}},
+		{`llm --prompt 'it''s'`, []string{"llm", "--prompt", "its"}},
+		{`llm --prompt "it's" '"quoted"'`, []string{"llm", "--prompt", "it's", `"quoted"`}},
+		{`My\ Tool/llm a\\b`, []string{"My Tool/llm", `a\b`}},
+		{`llm "a \"b\" \$HOME \n"`, []string{"llm", `a "b" $HOME \n`}},
+		{`llm 'a \" b'`, []string{"llm", `a \" b`}},
+		{`llm "" ''`, []string{"llm", "", ""}},
+		{`llm --opt="a b"c`, []string{"llm", "--opt=a bc"}},
+		{"llm\targ\nnext", []string{"llm", "arg", "next"}},
+		{``, nil},
+	}
+
+	for _, tt := range tests {
+		got, err := splitCommand(tt.line)
+		if err != nil {
+			t.Errorf("splitCommand(%q): %v", tt.line, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("splitCommand(%q) = %q, want %q", tt.line, got, tt.want)
+		}
+	}
+}
+
+func TestSplitCommandErrors(t *testing.T) {
+	for _, line := range []string{`llm "unterminated`, `llm 'unterminated`, `llm trailing\`} {
+		if got, err := splitCommand(line); err == nil {
+			t.Errorf("splitCommand(%q) = %q, want an error", line, got)
+		}
+	}
+}
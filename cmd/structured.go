@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/jkeresman01/mcp-client/client"
+	"github.com/jkeresman01/mcp-client/jsonschema"
+	"github.com/jkeresman01/mcp-client/transport"
+)
+
+var (
+	strictOutput   bool
+	structuredOnly bool
+)
+
+// checkStructuredOutput validates a result's structuredContent against the
+// tool's outputSchema. Problems are printed as a warning, or returned as an
+// error when strict is set. Results with isError set aren't checked, nor
+// are results on protocol versions without structured output.
+func checkStructuredOutput(ctx context.Context, s *client.Session, name string, result *client.CallToolResult, strict bool) error {
+	if result.IsError || !s.Features().StructuredOutput {
+		return nil
+	}
+
+	tool, err := s.Tool(ctx, name)
+	if err != nil || tool == nil || tool.OutputSchema == nil {
+		return nil
+	}
+
+	schema, err := jsonschema.Compile(tool.OutputSchema)
+	if err != nil {
+		fmt.Fprintf(diagnostics(), "Warning: not checking the result, the outputSchema of '%s' can't be used: %v\n", name, err)
+		return nil
+	}
+	schema.AssertFormats = true
+
+	var problems []string
+	if result.StructuredContent == nil {
+		problems = append(problems, "the tool declares an outputSchema but returned no structuredContent")
+	} else {
+		var invalid *jsonschema.ValidationError
+		if err := schema.Validate(result.StructuredContent); errors.As(err, &invalid) {
+			for _, e := range invalid.Errors {
+				problems = append(problems, e.String())
+			}
+		}
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+
+	if strict {
+		return &transport.MCPError{
+			Operation: "call-tool",
+			Err:       fmt.Errorf("structuredContent of tool '%s' doesn't match its outputSchema", name),
+			Hints: append(problems,
+				"See the tool's outputSchema: mcp-client list-tools",
+				"Show the result anyway: drop --strict",
+			),
+		}
+	}
+
+	fmt.Fprintf(diagnostics(), "Warning: structuredContent of tool '%s' doesn't match its outputSchema:\n  %s\n", name, strings.Join(problems, "\n  "))
+	return nil
+}
+
+// diagnostics is where warnings, debug output and questions for the user
+// go. --structured-only keeps stdout for the result, so they go to stderr.
+func diagnostics() *os.File {
+	if structuredOnly {
+		return os.Stderr
+	}
+	return os.Stdout
+}
+
+// printStructured prints just the structuredContent of a result, for
+// --structured-only. A failed tool's content goes to stderr instead.
+func printStructured(s *client.Session, name string, result *client.CallToolResult) error {
+	if result.IsError {
+		r := newContentRenderer(s.Features())
+		r.out = os.Stderr
+		r.inline = ""
+		for _, block := range result.Content {
+			r.block(block)
+		}
+		r.flushLinks()
+		return &errToolFailed{name: name}
+	}
+
+	if version := s.ProtocolVersion(); !s.Features().StructuredOutput {
+		return &transport.MCPError{
+			Operation: "call-tool",
+			Err:       fmt.Errorf("protocol version %s has no structured tool output", version),
+			Hints: []string{
+				"Structured output needs protocol version " + client.ProtocolVersion20250618 + " or later",
+				"Call it without --structured-only to see its content",
+			},
+		}
+	}
+
+	if result.StructuredContent == nil {
+		return &transport.MCPError{
+			Operation: "call-tool",
+			Err:       fmt.Errorf("tool '%s' returned no structuredContent", name),
+			Hints: []string{
+				"Call it without --structured-only to see its content",
+			},
+		}
+	}
+
+	out, _ := json.MarshalIndent(result.StructuredContent, "", "  ")
+	fmt.Println(string(out))
+	return nil
+}
+
+// duplicatesStructured reports whether a content block is the text form of
+// the structured content, which servers send for older clients
+func duplicatesStructured(block client.ContentBlock, structured interface{}) bool {
+	if block.Type != client.ContentText {
+		return false
+	}
+
+	var text interface{}
+	if err := json.Unmarshal([]byte(block.Text), &text); err != nil {
+		return false
+	}
+
+	// Compare the decoded forms so number types and key order don't matter
+	data, _ := json.Marshal(structured)
+	var decoded interface{}
+	json.Unmarshal(data, &decoded)
+	return reflect.DeepEqual(text, decoded)
+}
@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jkeresman01/mcp-client/transport"
+	"github.com/jkeresman01/mcp-client/uritemplate"
+)
+
+// parseTemplateVars turns key=value pairs into URI template variables.
+// Repeating a key or writing key[]=value builds a list, and
+// key[name]=value builds an associative array.
+func parseTemplateVars(pairs []string) (map[string]interface{}, error) {
+	vars := map[string]interface{}{}
+
+	for _, pair := range pairs {
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid variable %q, expected key=value", pair)
+		}
+
+		name, sub, indexed := strings.Cut(key, "[")
+		if indexed {
+			if !strings.HasSuffix(sub, "]") {
+				return nil, fmt.Errorf("invalid variable %q, expected key[]=value or key[name]=value", pair)
+			}
+			sub = strings.TrimSuffix(sub, "]")
+		}
+
+		switch existing := vars[name].(type) {
+		case nil:
+			switch {
+			case !indexed:
+				vars[name] = value
+			case sub == "":
+				vars[name] = []string{value}
+			default:
+				vars[name] = map[string]string{sub: value}
+			}
+
+		case string:
+			if indexed && sub != "" {
+				return nil, fmt.Errorf("variable %q is used both as a value and as an associative array", name)
+			}
+			vars[name] = []string{existing, value}
+
+		case []string:
+			if indexed && sub != "" {
+				return nil, fmt.Errorf("variable %q is used both as a list and as an associative array", name)
+			}
+			vars[name] = append(existing, value)
+
+		case map[string]string:
+			if !indexed || sub == "" {
+				return nil, fmt.Errorf("variable %q is used both as an associative array and as a list", name)
+			}
+			existing[sub] = value
+		}
+	}
+
+	return vars, nil
+}
+
+// expandTemplate expands a resource URI template with key=value pairs
+func expandTemplate(raw string, pairs []string) (string, error) {
+	tpl, err := uritemplate.Parse(raw)
+	if err != nil {
+		return "", &transport.MCPError{
+			Operation: "expanding resource template",
+			Err:       err,
+			Hints: []string{
+				"Templates follow RFC 6570, e.g. file:///{+path} or db://{table}/{id}",
+				"List the server's templates: mcp-client list-resource-templates",
+			},
+		}
+	}
+
+	vars, err := parseTemplateVars(pairs)
+	if err != nil {
+		return "", &transport.MCPError{
+			Operation: "expanding resource template",
+			Err:       err,
+			Hints: []string{
+				"Pass variables as --var key=value",
+				"Lists: --var key[]=a --var key[]=b, associative arrays: --var key[name]=value",
+			},
+		}
+	}
+
+	known := map[string]bool{}
+	for _, name := range tpl.Varnames() {
+		known[name] = true
+	}
+	for name := range vars {
+		if !known[name] {
+			return "", &transport.MCPError{
+				Operation: "expanding resource template",
+				Err:       fmt.Errorf("template %s has no variable %q", raw, name),
+				Hints: []string{
+					fmt.Sprintf("Template variables: %s", strings.Join(tpl.Varnames(), ", ")),
+				},
+			}
+		}
+	}
+
+	var unset []string
+	for _, name := range tpl.Varnames() {
+		if _, ok := vars[name]; !ok {
+			unset = append(unset, name)
+		}
+	}
+	if len(unset) > 0 {
+		fmt.Printf("Warning: template variables not set, expanding them as undefined: %s\n", strings.Join(unset, ", "))
+	}
+
+	uri, err := tpl.Expand(vars)
+	if err != nil {
+		return "", transport.WrapError("expanding resource template", err)
+	}
+
+	if debugMode {
+		fmt.Printf("Debug: Expanded %s to %s\n", raw, uri)
+	}
+	return uri, nil
+}
@@ -0,0 +1,22 @@
+//go:build !(linux || darwin || dragonfly || freebsd || netbsd || openbsd)
+
+package cmd
+
+import (
+	"errors"
+	"os"
+)
+
+// makeRaw is not supported here; the REPL falls back to plain line input
+func makeRaw(f *os.File) (restore func(), err error) {
+	return nil, errors.New("raw terminal mode is not supported on this platform")
+}
+
+// isTerminal reports whether f is attached to a terminal
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,48 @@
+//go:build linux || darwin || dragonfly || freebsd || netbsd || openbsd
+
+package cmd
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// makeRaw switches the terminal to character-at-a-time input without echo
+// and returns a function restoring the previous mode. Output processing is
+// left on so "\n" still starts a new line.
+func makeRaw(f *os.File) (restore func(), err error) {
+	fd := f.Fd()
+
+	var old syscall.Termios
+	if err := termios(fd, ioctlGetTermios, &old); err != nil {
+		return nil, err
+	}
+
+	raw := old
+	raw.Iflag &^= syscall.ICRNL | syscall.INLCR | syscall.IGNCR | syscall.IXON
+	raw.Lflag &^= syscall.ECHO | syscall.ECHONL | syscall.ICANON | syscall.ISIG | syscall.IEXTEN
+	raw.Cc[syscall.VMIN] = 1
+	raw.Cc[syscall.VTIME] = 0
+
+	if err := termios(fd, ioctlSetTermios, &raw); err != nil {
+		return nil, err
+	}
+
+	return func() { termios(fd, ioctlSetTermios, &old) }, nil
+}
+
+// isTerminal reports whether f is attached to a terminal. Unlike checking
+// for a character device, this is false for /dev/null.
+func isTerminal(f *os.File) bool {
+	var t syscall.Termios
+	return termios(f.Fd(), ioctlGetTermios, &t) == nil
+}
+
+func termios(fd uintptr, request uintptr, t *syscall.Termios) error {
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, request, uintptr(unsafe.Pointer(t)))
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/jkeresman01/mcp-client/client"
+)
+
+// toolCache is the tools/list result kept on disk per server, so the tool
+// subcommands, their --help and shell completion work without connecting
+type toolCache struct {
+	Server  *client.Implementation `json:"server,omitempty"`
+	Fetched time.Time              `json:"fetched"`
+	Tools   []client.Tool          `json:"tools"`
+}
+
+// find returns the cached tool called name, or nil
+func (c *toolCache) find(name string) *client.Tool {
+	for i := range c.Tools {
+		if c.Tools[i].Name == name {
+			return &c.Tools[i]
+		}
+	}
+	return nil
+}
+
+// toolCacheKey names the cache file of a connection: the configured server
+// name, or a hash of where the server is
+func toolCacheKey(server, transportName, url, command string, args []string) string {
+	if server != "" {
+		return "server-" + strings.Map(func(r rune) rune {
+			if r == '-' || r == '_' || r == '.' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+				return r
+			}
+			return '_'
+		}, server)
+	}
+
+	target := url
+	if transportName == "stdio" {
+		target = strings.Join(append([]string{command}, args...), " ")
+	}
+	sum := sha256.Sum256([]byte(transportName + "\n" + target))
+	return fmt.Sprintf("%s-%x", transportName, sum[:6])
+}
+
+// toolCachePath is where the tool list of the current connection is kept
+func toolCachePath(key string) (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "mcp-client", "tools", key+".json"), nil
+}
+
+func currentToolCacheKey() string {
+	return toolCacheKey(serverName, transportType, serverURL, commandPath, commandArgs)
+}
+
+// loadToolCache reads the cached tool list, or returns nil if there is none
+func loadToolCache(key string) *toolCache {
+	path, err := toolCachePath(key)
+	if err != nil {
+		return nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+
+	var cache toolCache
+	if err := json.Unmarshal(data, &cache); err != nil {
+		return nil
+	}
+	return &cache
+}
+
+// saveToolCache stores a complete tool list for the current connection.
+// The cache is only a convenience, so failures are reported in debug mode
+// only.
+func saveToolCache(s *client.Session, tools []client.Tool) *toolCache {
+	info := s.ServerInfo()
+	cache := &toolCache{
+		Server:  &info,
+		Fetched: time.Now().UTC(),
+		Tools:   tools,
+	}
+
+	path, err := toolCachePath(currentToolCacheKey())
+	if err == nil {
+		err = os.MkdirAll(filepath.Dir(path), 0755)
+	}
+	if err == nil {
+		data, _ := json.MarshalIndent(cache, "", "  ")
+		err = os.WriteFile(path, data, 0644)
+	}
+	if err != nil && debugMode {
+		fmt.Fprintf(diagnostics(), "Debug: Could not cache the tool list: %v\n", err)
+	}
+	return cache
+}
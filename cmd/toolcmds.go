@@ -0,0 +1,422 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jkeresman01/mcp-client/client"
+	"github.com/jkeresman01/mcp-client/jsonschema"
+	"github.com/jkeresman01/mcp-client/transport"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// toolJSONArgs holds --json of the tool subcommands
+var toolJSONArgs string
+
+// maxFlagDepth is how deep nested object properties get flags of their
+// own, e.g. --filter.since
+const maxFlagDepth = 2
+
+var toolCmd = &cobra.Command{
+	Use:   "tool",
+	Short: "Call the server's tools as subcommands, with flags from their inputSchema",
+	Long: `Each tool the server lists becomes a subcommand with a flag per argument.
+Flag types, required markers, allowed values and help text come from the
+tool's inputSchema and description:
+
+  mcp-client tool search --query golang --limit 10 --tags a --tags b
+  mcp-client tool search --help
+
+The tool list is cached per server, so --help and shell completion work
+without connecting. Running 'mcp-client tool' on its own fetches the list
+again and shows it; 'list-tools' refreshes the cache as well. A tool that
+isn't cached yet is looked up on the server when it is called.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		s, err := connect(cmd.Context())
+		if err != nil {
+			return err
+		}
+		defer s.Close()
+
+		ctx, cancel := requestContext(cmd.Context())
+		defer cancel()
+
+		result, err := s.ListAllTools(ctx, client.PageOptions{})
+		if err != nil {
+			return transport.WrapError("list-tools", err)
+		}
+		cache := saveToolCache(s, result.Items)
+
+		printToolCommands(cache)
+		return nil
+	},
+}
+
+// addToolCommands registers a subcommand of 'tool' for every cached tool,
+// before cobra parses the command line. The connection flags are read
+// ahead of cobra to find the right cache; a tool missing from it gets a
+// stand-in that looks it up on the server.
+func addToolCommands(args []string) {
+	positional, conn := prescanArgs(args)
+
+	completing := len(positional) > 0 && (positional[0] == cobra.ShellCompRequestCmd || positional[0] == cobra.ShellCompNoDescRequestCmd)
+	if completing {
+		positional = positional[1:]
+	}
+	if len(positional) == 0 || positional[0] != toolCmd.Name() {
+		return
+	}
+
+	cache := loadToolCache(conn)
+	if cache != nil {
+		for i := range cache.Tools {
+			if sub := newToolCommand(&cache.Tools[i]); sub != nil {
+				toolCmd.AddCommand(sub)
+			}
+		}
+	}
+
+	if len(positional) > 1 && !completing && (cache == nil || cache.find(positional[1]) == nil) {
+		toolCmd.AddCommand(newUncachedToolCommand(positional[1], argsAfter(args, positional[1])))
+	}
+}
+
+// prescanArgs finds the positional arguments and the tool cache key of
+// the command line, without touching the real flags. Every persistent
+// flag is known so their values aren't mistaken for positionals.
+func prescanArgs(args []string) ([]string, string) {
+	fs := pflag.NewFlagSet("prescan", pflag.ContinueOnError)
+	fs.ParseErrorsWhitelist.UnknownFlags = true
+	fs.SetOutput(nopWriter{})
+
+	var server, transportName, url, command string
+	var commandArguments []string
+
+	// pflag gives up on an unknown --help, even with unknown flags allowed
+	fs.BoolP("help", "h", false, "")
+
+	rootCmd.PersistentFlags().VisitAll(func(f *pflag.Flag) {
+		switch f.Name {
+		case "server":
+			fs.StringVar(&server, f.Name, serverName, "")
+		case "transport":
+			fs.StringVar(&transportName, f.Name, transportType, "")
+		case "url":
+			fs.StringVar(&url, f.Name, serverURL, "")
+		case "command":
+			fs.StringVar(&command, f.Name, commandPath, "")
+		case "args":
+			fs.StringSliceVar(&commandArguments, f.Name, commandArgs, "")
+		default:
+			if f.Value.Type() == "bool" {
+				fs.Bool(f.Name, false, "")
+			} else {
+				fs.String(f.Name, "", "")
+			}
+		}
+	})
+
+	if err := fs.Parse(args); err != nil {
+		return nil, ""
+	}
+	return fs.Args(), toolCacheKey(server, transportName, url, command, commandArguments)
+}
+
+// argsAfter returns what follows the tool name on the command line
+func argsAfter(args []string, name string) []string {
+	seenTool := false
+	for i, arg := range args {
+		if arg == toolCmd.Name() {
+			seenTool = true
+		} else if seenTool && arg == name {
+			return args[i+1:]
+		}
+	}
+	return nil
+}
+
+// newUncachedToolCommand stands in for a tool that isn't in the cache. Its
+// flags depend on the inputSchema, so it fetches the tool list, caches it
+// and parses the command line again with the real tool command.
+func newUncachedToolCommand(name string, rawArgs []string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                name,
+		Short:              "Not cached yet; looked up on the server when called",
+		Args:               cobra.ArbitraryArgs,
+		FParseErrWhitelist: cobra.FParseErrWhitelist{UnknownFlags: true},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+
+			s, err := connect(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			ctx, cancel := requestContext(cmd.Context())
+			result, err := s.ListAllTools(ctx, client.PageOptions{})
+			cancel()
+			if err != nil {
+				s.Close()
+				return transport.WrapError("list-tools", err)
+			}
+			cache := saveToolCache(s, result.Items)
+			s.Close()
+
+			real := newToolCommand(cache.find(name))
+			if real == nil {
+				return transport.NewToolNotFoundError(name)
+			}
+
+			// Persistent flags were parsed already; skip them this time
+			real.FParseErrWhitelist.UnknownFlags = true
+			real.SetContext(cmd.Context())
+			if err := real.ParseFlags(rawArgs); err != nil {
+				return err
+			}
+			return real.RunE(real, real.Flags().Args())
+		},
+	}
+
+	// Declared here too so --structured-only is seen before RunE
+	addToolCallFlags(cmd.Flags())
+	return cmd
+}
+
+// toolFlag collects the values given for one tool argument. They become
+// key=value pairs, converted like --arg.
+type toolFlag struct {
+	key    string
+	typ    string
+	values []string
+	schema *jsonschema.Schema
+	node   interface{}
+}
+
+func (f *toolFlag) String() string { return strings.Join(f.values, ",") }
+func (f *toolFlag) Type() string   { return f.typ }
+
+func (f *toolFlag) Set(v string) error {
+	// Check the conversion now so the error names the flag; @file values
+	// are read later
+	if !strings.HasPrefix(v, "@") {
+		if _, err := coerceArg(f.schema, f.node, v); err != nil {
+			return err
+		}
+	}
+	f.values = append(f.values, v)
+	return nil
+}
+
+// pairs turns the flag's values into key=value pairs: repeated list flags
+// append items, anything else keeps the last value
+func (f *toolFlag) pairs() []string {
+	if len(f.values) == 0 {
+		return nil
+	}
+	if f.typ == "list" && len(f.values) > 1 {
+		pairs := make([]string, len(f.values))
+		for i, v := range f.values {
+			pairs[i] = f.key + "[]=" + v
+		}
+		return pairs
+	}
+	return []string{f.key + "=" + f.values[len(f.values)-1]}
+}
+
+// newToolCommand builds the subcommand for a tool from tools/list, or nil
+// if its name can't be a command
+func newToolCommand(tool *client.Tool) *cobra.Command {
+	if tool == nil {
+		return nil
+	}
+	name := tool.Name
+	if name == "" || strings.ContainsAny(name, " \t\n") || strings.HasPrefix(name, "-") {
+		return nil
+	}
+
+	description := strings.TrimSpace(tool.Description)
+	short, _, _ := strings.Cut(description, "\n")
+	if short == "" && tool.DisplayName() != name {
+		short = tool.DisplayName()
+	}
+
+	cmd := &cobra.Command{
+		Use:   name,
+		Short: short,
+		Long: description + `
+
+Arguments not covered by the flags can be given with --json '{...}' and
+--arg key=value; flags win over --json and --arg wins over both.`,
+		Args: cobra.NoArgs,
+	}
+
+	var flags []*toolFlag
+	if schema, err := jsonschema.Compile(tool.InputSchema); err == nil {
+		addSchemaFlags(cmd, schema, schema.Root(), "", &flags, 0)
+	}
+
+	addToolCallFlags(cmd.Flags())
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		var base map[string]interface{}
+		if err := json.Unmarshal([]byte(toolJSONArgs), &base); err != nil {
+			return transport.NewInvalidArgumentsError(err.Error())
+		}
+
+		var pairs []string
+		for _, f := range flags {
+			pairs = append(pairs, f.pairs()...)
+		}
+		return runToolCall(cmd, name, base, append(pairs, toolArgPairs...))
+	}
+	return cmd
+}
+
+// addToolCallFlags adds the flags every tool subcommand has besides its
+// arguments
+func addToolCallFlags(flags *pflag.FlagSet) {
+	flags.StringVar(&toolJSONArgs, "json", "{}", "Arguments as a JSON object")
+	flags.StringArrayVar(&toolArgPairs, "arg", nil, "Argument as key=value, converted to the type in the tool's inputSchema (repeatable)")
+	flags.BoolVar(&showProgress, "progress", true, "Request progress notifications and show them on stderr")
+	flags.BoolVar(&noValidate, "no-validate", false, "Don't check the arguments against the tool's inputSchema before calling it")
+	flags.BoolVar(&strictOutput, "strict", false, "Fail if structuredContent doesn't match the tool's outputSchema (default: warn)")
+	flags.BoolVar(&structuredOnly, "structured-only", false, "Print only the structuredContent as JSON, e.g. to pipe into jq")
+}
+
+// reservedFlag reports whether a flag name is taken by the client itself,
+// in which case the argument's flag is prefixed with "arg-"
+func reservedFlag(name string) bool {
+	switch name {
+	case "help", "json", "arg", "progress", "no-validate", "strict", "structured-only":
+		return true
+	}
+	return rootCmd.PersistentFlags().Lookup(name) != nil
+}
+
+// addSchemaFlags adds a flag for every property of node. Objects with
+// properties of their own also get flags for those, as --parent.child.
+func addSchemaFlags(cmd *cobra.Command, schema *jsonschema.Schema, node interface{}, prefix string, flags *[]*toolFlag, depth int) {
+	required := map[string]bool{}
+	for _, name := range schema.Required(node) {
+		required[name] = true
+	}
+
+	for _, property := range schema.Properties(node) {
+		// Keys with these would not survive the trip through --arg
+		if property == "" || strings.ContainsAny(property, ".[]= ") {
+			continue
+		}
+
+		sub := schema.Property(node, property)
+		key := prefix + property
+		f := &toolFlag{key: key, typ: flagType(schema.Types(sub)), schema: schema, node: sub}
+
+		name := key
+		if reservedFlag(name) {
+			name = "arg-" + name
+		}
+		// Another property took the name, e.g. "arg-json" next to "json";
+		// this one is left to --arg
+		if cmd.Flags().Lookup(name) != nil {
+			continue
+		}
+		flag := cmd.Flags().VarPF(f, name, "", argUsage(schema, sub, required[property]))
+		if f.typ == "bool" {
+			flag.NoOptDefVal = "true"
+		}
+		*flags = append(*flags, f)
+
+		if values := enumStrings(schema.Enum(sub)); len(values) > 0 {
+			cmd.RegisterFlagCompletionFunc(name, cobra.FixedCompletions(values, cobra.ShellCompDirectiveNoFileComp))
+		}
+
+		if f.typ == "json" && depth < maxFlagDepth {
+			addSchemaFlags(cmd, schema, sub, key+".", flags, depth+1)
+		}
+	}
+}
+
+// flagType names a flag's type in --help after the JSON types it takes
+func flagType(types []string) string {
+	if len(types) != 1 {
+		return "value"
+	}
+	switch types[0] {
+	case "integer":
+		return "int"
+	case "number":
+		return "float"
+	case "boolean":
+		return "bool"
+	case "array":
+		return "list"
+	case "object":
+		return "json"
+	}
+	return types[0]
+}
+
+// argUsage is the help text of an argument's flag
+func argUsage(schema *jsonschema.Schema, node interface{}, required bool) string {
+	var parts []string
+
+	if description, ok := schema.Keyword(node, "description"); ok {
+		line, _, _ := strings.Cut(strings.TrimSpace(fmt.Sprint(description)), "\n")
+		parts = append(parts, line)
+	} else if title, ok := schema.Keyword(node, "title"); ok {
+		parts = append(parts, fmt.Sprint(title))
+	}
+	if values := enumStrings(schema.Enum(node)); len(values) > 0 {
+		parts = append(parts, "one of: "+strings.Join(values, ", "))
+	}
+	if def, ok := schema.Keyword(node, "default"); ok {
+		out, _ := json.Marshal(def)
+		parts = append(parts, "(default "+string(out)+")")
+	}
+	if required {
+		parts = append(parts, "(required)")
+	}
+	return strings.Join(parts, " ")
+}
+
+func enumStrings(values []interface{}) []string {
+	strs := make([]string, 0, len(values))
+	for _, v := range values {
+		if v != nil {
+			strs = append(strs, fmt.Sprint(v))
+		}
+	}
+	return strs
+}
+
+// printToolCommands lists the tools as subcommands
+func printToolCommands(cache *toolCache) {
+	type entry struct{ name, short string }
+	var entries []entry
+	width := 0
+
+	for i := range cache.Tools {
+		if sub := newToolCommand(&cache.Tools[i]); sub != nil {
+			entries = append(entries, entry{sub.Name(), sub.Short})
+			width = max(width, len(sub.Name()))
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].name < entries[j].name })
+
+	fmt.Printf("%d tools, cached for --help and completion:\n", len(entries))
+	for _, e := range entries {
+		fmt.Printf("  %-*s  %s\n", width, e.name, e.short)
+	}
+	fmt.Printf("\nRun 'mcp-client tool <name> --help' for a tool's flags.\n")
+}
+
+func init() {
+	toolCmd.ValidArgsFunction = toolCommandCompletion
+	rootCmd.AddCommand(toolCmd)
+}
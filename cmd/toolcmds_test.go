@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/jkeresman01/mcp-client/client"
+)
+
+func TestToolCommandFlagNames(t *testing.T) {
+	var schema interface{}
+	json.Unmarshal([]byte(`{
+		"type": "object",
+		"properties": {
+			"query": {"type": "string"},
+			"json": {"type": "string"},
+			"arg-json": {"type": "string"},
+			"server": {"type": "string"},
+			"arg-server": {"type": "string"},
+			"filter": {"type": "object", "properties": {"since": {"type": "string"}}}
+		}
+	}`), &schema)
+
+	cmd := newToolCommand(&client.Tool{Name: "search", InputSchema: schema})
+	if cmd == nil {
+		t.Fatal("newToolCommand() = nil")
+	}
+
+	// "json" and "server" are the client's, so the arguments move to
+	// "arg-json" and "arg-server", which those properties had already taken;
+	// they are left to --arg
+	for _, name := range []string{"query", "arg-json", "arg-server", "filter", "filter.since"} {
+		if cmd.Flags().Lookup(name) == nil {
+			t.Errorf("no --%s flag", name)
+		}
+	}
+	if f := cmd.Flags().Lookup("json"); f == nil || f.Usage != "Arguments as a JSON object" {
+		t.Errorf("--json = %v, want the client's flag", f)
+	}
+}
@@ -2,58 +2,60 @@ package cmd
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
-	"github.com/jkeresman01/mcp-client/transport"
+	"os"
 
+	"github.com/jkeresman01/mcp-client/client"
+	"github.com/jkeresman01/mcp-client/transport"
 	"github.com/spf13/cobra"
 )
 
 var (
-	toolName string
-	toolArgs string
+	toolName     string
+	toolArgs     string
+	showProgress bool
 )
 
 var listToolsCmd = &cobra.Command{
 	Use:   "list-tools",
 	Short: "List all registered MCP tools",
-	Long:  `Retrieve and display all tools available on the MCP server.`,
+	Long: `Retrieve and display all tools available on the MCP server.
+Follows nextCursor until every page has been fetched unless --no-follow or
+--page-size is given.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		t, err := getTransport()
+		s, err := connect(cmd.Context())
 		if err != nil {
 			return err
 		}
-		defer t.Close()
-
-		req := transport.RPCRequest{
-			JSONRPC: "2.0",
-			ID:      2,
-			Method:  "tools/list",
-			Params:  map[string]interface{}{},
-		}
+		defer s.Close()
 
-		if debugMode {
-			fmt.Println("Debug: Sending request:", req.Method)
-		}
+		ctx, cancel := requestContext(cmd.Context())
+		defer cancel()
 
-		resp, err := t.Send(req)
+		result, err := s.ListAllTools(ctx, pageOptions())
 		if err != nil {
+			var rpcErr *transport.RPCError
+			if errors.As(err, &rpcErr) {
+				return &transport.MCPError{
+					Operation: "list-tools",
+					Err:       fmt.Errorf("server returned error: %v", rpcErr),
+					Hints: []string{
+						"The server may not support tools",
+						"Run 'mcp-client init' to see the server's capabilities",
+						"Check server logs for more details",
+					},
+				}
+			}
 			return transport.WrapError("list-tools", err)
 		}
-
-		if resp.Error != nil {
-			return &transport.MCPError{
-				Operation: "list-tools",
-				Err:       fmt.Errorf("server returned error: %v", resp.Error),
-				Hints: []string{
-					"The server may not have initialized properly",
-					"Try running 'mcp-client init' first",
-					"Check server logs for more details",
-				},
-			}
+		if listCursor == "" && result.NextCursor == "" {
+			saveToolCache(s, result.Items)
 		}
 
-		out, _ := json.MarshalIndent(resp.Result, "", "  ")
+		out, _ := json.MarshalIndent(listOutput("tools", result), "", "  ")
 		fmt.Println("Tools:\n", string(out))
+		printMoreHint(result)
 		return nil
 	},
 }
@@ -62,11 +64,23 @@ var callToolCmd = &cobra.Command{
 	Use:   "call-tool",
 	Short: "Call an MCP tool by name with JSON args",
 	Long: `Execute a specific tool on the MCP server with provided arguments.
-Arguments should be provided as a JSON string.
+Arguments should be provided as a JSON string. Progress notifications sent
+by the server are rendered on stderr while the tool runs.
+
+Before the call the arguments are checked against the tool's inputSchema
+from tools/list, reporting every problem with its JSON path. --no-validate
+sends them as they are.
+
+Arguments can also be given one at a time with --arg key=value, merged
+over --args. Values are converted to the types the tool's inputSchema
+declares; dots nest objects, key[]=value appends to a list and
+key[N]=value sets an item. key=@file reads the value from a file.
 
 Examples:
   mcp-client call-tool --name calculator --args '{"op":"add","a":5,"b":3}'
-  mcp-client call-tool --name search --args '{"query":"golang"}' --server prod`,
+  mcp-client call-tool --name search --args '{"query":"golang"}' --server prod
+  mcp-client call-tool --name search --arg query=golang --arg limit=10 --arg tags[]=a --arg filter.since=2024-01-01
+  mcp-client call-tool --name summarize --arg text=@notes.txt`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if toolName == "" {
 			return &transport.MCPError{
@@ -79,46 +93,60 @@ Examples:
 			}
 		}
 
-		t, err := getTransport()
-		if err != nil {
-			return err
-		}
-		defer t.Close()
-
 		var parsedArgs map[string]interface{}
 		if err := json.Unmarshal([]byte(toolArgs), &parsedArgs); err != nil {
 			return transport.NewInvalidArgumentsError(err.Error())
 		}
 
-		req := transport.RPCRequest{
-			JSONRPC: "2.0",
-			ID:      3,
-			Method:  "tools/call",
-			Params: map[string]interface{}{
-				"name":      toolName,
-				"arguments": parsedArgs,
-			},
-		}
-
-		if debugMode {
-			fmt.Printf("Debug: Calling tool '%s' with args: %s\n", toolName, toolArgs)
-		}
-
-		resp, err := t.Send(req)
-		if err != nil {
-			return transport.WrapError("call-tool", err)
-		}
+		return runToolCall(cmd, toolName, parsedArgs, toolArgPairs)
+	},
+}
 
-		if resp.Error != nil {
-			errMap, ok := resp.Error.(map[string]interface{})
-			if ok {
-				if code, exists := errMap["code"]; exists && code == -32601 {
-					return transport.NewToolNotFoundError(toolName)
-				}
+// runToolCall connects, builds the arguments from base and key=value pairs,
+// validates them and calls the tool, for call-tool and the tool
+// subcommands
+func runToolCall(cmd *cobra.Command, name string, base map[string]interface{}, pairs []string) error {
+	s, err := connect(cmd.Context())
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+
+	ctx, cancel := requestContext(cmd.Context())
+	defer cancel()
+
+	args, err := buildToolArgs(ctx, s, name, base, pairs)
+	if err != nil {
+		return err
+	}
+
+	if debugMode {
+		argsJSON, _ := json.Marshal(args)
+		fmt.Fprintf(diagnostics(), "Debug: Calling tool '%s' with args: %s\n", name, argsJSON)
+	}
+
+	if err := validateToolArgs(ctx, s, name, args); err != nil {
+		cmd.SilenceUsage = true
+		return err
+	}
+
+	progress := newProgressRenderer(os.Stderr)
+	var onProgress func(client.Progress)
+	if showProgress {
+		onProgress = progress.Update
+	}
+
+	result, err := s.CallToolWithProgress(ctx, name, args, onProgress)
+	progress.Finish()
+	if err != nil {
+		var rpcErr *transport.RPCError
+		if errors.As(err, &rpcErr) {
+			if rpcErr.Code == transport.CodeMethodNotFound {
+				return transport.NewToolNotFoundError(name)
 			}
 			return &transport.MCPError{
 				Operation: "call-tool",
-				Err:       fmt.Errorf("server error: %v", resp.Error),
+				Err:       fmt.Errorf("server error: %v", rpcErr),
 				Hints: []string{
 					"Verify the tool name is correct (case-sensitive)",
 					"Check that all required arguments are provided",
@@ -126,17 +154,34 @@ Examples:
 				},
 			}
 		}
-
-		out, _ := json.MarshalIndent(resp.Result, "", "  ")
-		fmt.Println("Tool Result:\n", string(out))
-		return nil
-	},
+		return transport.WrapError("call-tool", err)
+	}
+
+	// The tool's own output says what went wrong
+	cmd.SilenceUsage = true
+
+	if err := checkStructuredOutput(ctx, s, name, result, strictOutput); err != nil {
+		return err
+	}
+	if structuredOnly {
+		return printStructured(s, name, result)
+	}
+	return renderToolResult(name, result, s.Features())
 }
 
 func init() {
 	callToolCmd.Flags().StringVar(&toolName, "name", "", "Name of the tool to call (required)")
 	callToolCmd.Flags().StringVar(&toolArgs, "args", "{}", "JSON-encoded arguments to pass to the tool")
+	callToolCmd.Flags().StringArrayVar(&toolArgPairs, "arg", nil, "Argument as key=value, converted to the type in the tool's inputSchema (key.sub=v, key[]=v, key=@file; repeatable)")
+	callToolCmd.Flags().BoolVar(&showProgress, "progress", true, "Request progress notifications and show them on stderr")
+	callToolCmd.Flags().BoolVar(&noValidate, "no-validate", false, "Don't check the arguments against the tool's inputSchema before calling it")
+	callToolCmd.Flags().BoolVar(&strictOutput, "strict", false, "Fail if structuredContent doesn't match the tool's outputSchema (default: warn)")
+	callToolCmd.Flags().BoolVar(&structuredOnly, "structured-only", false, "Print only the structuredContent as JSON, e.g. to pipe into jq")
 	callToolCmd.MarkFlagRequired("name")
+	callToolCmd.RegisterFlagCompletionFunc("name", toolNameCompletion)
+	callToolCmd.RegisterFlagCompletionFunc("arg", toolArgCompletion)
+
+	addPagingFlags(listToolsCmd)
 
 	rootCmd.AddCommand(listToolsCmd)
 	rootCmd.AddCommand(callToolCmd)
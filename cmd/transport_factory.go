@@ -1,13 +1,131 @@
 package cmd
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
+
+	"github.com/jkeresman01/mcp-client/client"
 	"github.com/jkeresman01/mcp-client/transport"
 )
 
+// connect creates the transport and runs the initialize handshake
+func connect(parent context.Context) (*client.Session, error) {
+	s, err := newSession()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := initializeSession(parent, s); err != nil {
+		s.Close()
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// connectForCompletion connects for shell completion. The session answers
+// no server requests, so it advertises no client capabilities: nobody is
+// there to approve a sampling request or fill in a form while completing.
+func connectForCompletion(parent context.Context) (*client.Session, error) {
+	t, err := getTransport()
+	if err != nil {
+		return nil, err
+	}
+
+	s := client.NewSession(t)
+	s.SetProtocolVersion(protoVersion)
+	if err := initializeSession(parent, s); err != nil {
+		s.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// newSession creates a session without initializing it
+func newSession() (*client.Session, error) {
+	t, err := getTransport()
+	if err != nil {
+		return nil, err
+	}
+
+	s := client.NewSession(t)
+	s.SetProtocolVersion(protoVersion)
+	if debugMode {
+		s.SetTrace(func(direction string, msg interface{}) {
+			data, _ := json.MarshalIndent(msg, "", "  ")
+			fmt.Fprintf(diagnostics(), "Debug: %s %s\n", direction, string(data))
+		})
+	}
+
+	roots, err := configuredRoots()
+	if err != nil {
+		t.Close()
+		return nil, err
+	}
+	s.SetRoots(context.Background(), roots)
+
+	if err := installSampling(s); err != nil {
+		t.Close()
+		return nil, err
+	}
+
+	if err := installElicitation(s); err != nil {
+		t.Close()
+		return nil, err
+	}
+
+	if err := installServerLog(s); err != nil {
+		t.Close()
+		return nil, err
+	}
+
+	return s, nil
+}
+
+func initializeSession(parent context.Context, s *client.Session) error {
+	ctx, cancel := requestContext(parent)
+	defer cancel()
+
+	_, err := s.Initialize(ctx)
+	if err == nil {
+		applyServerLogLevel(parent, s)
+		return nil
+	}
+
+	var versionErr *client.UnsupportedVersionError
+	if errors.As(err, &versionErr) {
+		return &transport.MCPError{
+			Operation: "initialize",
+			Err:       versionErr,
+			Hints: []string{
+				fmt.Sprintf("This client supports protocol versions: %s", strings.Join(client.SupportedProtocolVersions, ", ")),
+				"Pin a version both sides support: --protocol-version <version>",
+				"Or drop --protocol-version / protocol_version to let the client negotiate",
+			},
+		}
+	}
+
+	var rpcErr *transport.RPCError
+	if errors.As(err, &rpcErr) {
+		return &transport.MCPError{
+			Operation: "initialize",
+			Err:       fmt.Errorf("server returned error: %v", rpcErr),
+			Hints: []string{
+				"The server might not support this MCP protocol version",
+				"Check if the server is properly configured",
+				"Verify the transport type matches the server's configuration",
+			},
+		}
+	}
+	return transport.WrapError("initialize", err)
+}
+
 func getTransport() (transport.Transport, error) {
 	if debugMode {
-		fmt.Printf("Debug: Creating %s transport\n", transportType)
+		fmt.Fprintf(diagnostics(), "Debug: Creating %s transport\n", transportType)
 	}
 
 	switch transportType {
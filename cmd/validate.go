@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jkeresman01/mcp-client/client"
+	"github.com/jkeresman01/mcp-client/jsonschema"
+	"github.com/jkeresman01/mcp-client/transport"
+)
+
+// noValidate skips checking tool arguments against the tool's inputSchema
+var noValidate bool
+
+// validateToolArgs checks args against the inputSchema the server lists for
+// the tool, so mistakes are caught before the call with the exact path of
+// each problem. A tool that isn't listed, or a schema this client can't
+// use, is left to the server.
+func validateToolArgs(ctx context.Context, s *client.Session, name string, args map[string]interface{}) error {
+	if noValidate {
+		return nil
+	}
+
+	tool, err := s.Tool(ctx, name)
+	if err != nil {
+		if debugMode {
+			fmt.Fprintf(diagnostics(), "Debug: Not validating arguments, tools/list failed: %v\n", err)
+		}
+		return nil
+	}
+	if tool == nil || tool.InputSchema == nil {
+		return nil
+	}
+
+	compiled, err := jsonschema.Compile(tool.InputSchema)
+	if err != nil {
+		fmt.Fprintf(diagnostics(), "Warning: not validating arguments, the inputSchema of '%s' can't be used: %v\n", name, err)
+		return nil
+	}
+	compiled.AssertFormats = true
+
+	var instance interface{} = args
+	if args == nil {
+		instance = map[string]interface{}{}
+	}
+
+	var invalid *jsonschema.ValidationError
+	if err := compiled.Validate(instance); !errors.As(err, &invalid) {
+		return nil
+	}
+
+	var hints []string
+	for _, e := range invalid.Errors {
+		hints = append(hints, e.String())
+	}
+	hints = append(hints,
+		"See the tool's inputSchema: mcp-client list-tools",
+		"Send the arguments anyway: --no-validate",
+	)
+
+	return &transport.MCPError{
+		Operation: "call-tool",
+		Err:       fmt.Errorf("arguments for tool '%s' don't match its inputSchema", name),
+		Hints:     hints,
+	}
+}
@@ -0,0 +1,194 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/jkeresman01/mcp-client/client"
+	"github.com/jkeresman01/mcp-client/transport"
+	"github.com/spf13/cobra"
+)
+
+var (
+	watchResourceID string
+	watchDiff       bool
+)
+
+var watchResourceCmd = &cobra.Command{
+	Use:   "watch-resource",
+	Short: "Print a resource every time it changes",
+	Long: `Subscribe to a resource and print it again every time the server sends
+notifications/resources/updated for it. With --diff only the changed lines
+are printed. Press Ctrl-C to unsubscribe and exit.
+
+The server must advertise the resources.subscribe capability.
+
+Examples:
+  mcp-client watch-resource --id file:///var/log/app.log --diff
+  mcp-client watch-resource --id resource://status --server prod`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		s, err := connect(cmd.Context())
+		if err != nil {
+			return err
+		}
+		defer s.Close()
+
+		return watchResource(cmd.Context(), s, watchResourceID, watchDiff)
+	},
+}
+
+// watchResource subscribes to uri and reprints it on every update until
+// Ctrl-C, then unsubscribes
+func watchResource(parent context.Context, s *client.Session, uri string, showDiff bool) error {
+	if !s.CanSubscribe() {
+		return &transport.MCPError{
+			Operation: "watch-resource",
+			Err:       fmt.Errorf("server does not support resource subscriptions"),
+			Hints: []string{
+				"The server must advertise the resources.subscribe capability",
+				"Run 'mcp-client init' to see the server's capabilities",
+				fmt.Sprintf("Read the resource once instead: mcp-client get-resource --id %s", uri),
+			},
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(parent, os.Interrupt)
+	defer stop()
+
+	previous, err := readAndPrintResource(ctx, s, uri, "", false)
+	if err != nil {
+		return err
+	}
+
+	// Updates that arrive while a read is in flight collapse into one re-read
+	updated := make(chan struct{}, 1)
+	remove := s.OnResourceUpdated(func(updatedURI string) {
+		if updatedURI != uri {
+			return
+		}
+		select {
+		case updated <- struct{}{}:
+		default:
+		}
+	})
+	defer remove()
+
+	subCtx, cancel := requestContext(ctx)
+	err = s.Subscribe(subCtx, uri)
+	cancel()
+	if err != nil {
+		return subscribeError(uri, err)
+	}
+	defer unsubscribe(s, uri)
+
+	fmt.Printf("Watching %s, press Ctrl-C to stop\n", uri)
+
+	for {
+		select {
+		case <-ctx.Done():
+			fmt.Println()
+			return nil
+		case <-updated:
+		}
+
+		fmt.Printf("\n[%s] %s updated\n", time.Now().Format("15:04:05"), uri)
+
+		text, err := readAndPrintResource(ctx, s, uri, previous, showDiff)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			fmt.Printf("Warning: %v\n", resourceError(uri, err))
+			continue
+		}
+		previous = text
+	}
+}
+
+// readAndPrintResource reads uri and prints it in full, or as a diff against
+// previous when showDiff is set. It returns the resource's text form.
+func readAndPrintResource(parent context.Context, s *client.Session, uri, previous string, showDiff bool) (string, error) {
+	ctx, cancel := requestContext(parent)
+	defer cancel()
+
+	result, err := s.ReadResource(ctx, uri)
+	if err != nil {
+		return "", resourceError(uri, err)
+	}
+
+	text := resourceText(result)
+	if !showDiff {
+		renderResource(result, s.Features())
+		return text, nil
+	}
+
+	if !printDiff(os.Stdout, previous, text, isTerminal(os.Stdout)) {
+		fmt.Println("(no changes)")
+	}
+	return text, nil
+}
+
+// resourceText flattens resources/read contents into text for diffing.
+// Binary contents are summarized rather than compared byte by byte.
+func resourceText(result *client.ReadResourceResult) string {
+	var sb strings.Builder
+	for _, item := range result.Contents {
+		if len(result.Contents) > 1 {
+			fmt.Fprintf(&sb, "# %s\n", item.URI)
+		}
+
+		if item.Blob != "" {
+			fmt.Fprintf(&sb, "[binary %s, %d bytes base64]\n", item.MimeType, len(item.Blob))
+		} else {
+			sb.WriteString(item.Text)
+		}
+	}
+	return sb.String()
+}
+
+// unsubscribe ends a subscription. It runs after Ctrl-C, so it gets a fresh
+// context of its own.
+func unsubscribe(s *client.Session, uri string) {
+	ctx, cancel := requestContext(context.Background())
+	defer cancel()
+
+	if err := s.Unsubscribe(ctx, uri); err != nil {
+		fmt.Printf("Warning: failed to unsubscribe from %s: %v\n", uri, err)
+		return
+	}
+
+	if debugMode {
+		fmt.Printf("Debug: Unsubscribed from %s\n", uri)
+	}
+}
+
+func subscribeError(uri string, err error) error {
+	var rpcErr *transport.RPCError
+	if !errors.As(err, &rpcErr) {
+		return transport.WrapError("watch-resource", err)
+	}
+
+	return &transport.MCPError{
+		Operation: "watch-resource",
+		Err:       fmt.Errorf("failed to subscribe to '%s': %v", uri, rpcErr),
+		Hints: []string{
+			"List available resources: mcp-client list-resources",
+			"Check if the resource URI is correct",
+			"Check server logs for more details",
+		},
+	}
+}
+
+func init() {
+	watchResourceCmd.Flags().StringVar(&watchResourceID, "id", "", "ID/URI of the resource to watch")
+	watchResourceCmd.Flags().BoolVar(&watchDiff, "diff", false, "Print only the lines that changed")
+	watchResourceCmd.MarkFlagRequired("id")
+	watchResourceCmd.RegisterFlagCompletionFunc("id", resourceURICompletion)
+
+	rootCmd.AddCommand(watchResourceCmd)
+}
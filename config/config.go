@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 )
 
 type ServerConfig struct {
@@ -12,6 +13,49 @@ type ServerConfig struct {
 	Transport string   `json:"transport"`
 	Command   string   `json:"command,omitempty"`
 	Args      []string `json:"args,omitempty"`
+	Timeout   string   `json:"timeout,omitempty"`
+	// ProtocolVersion pins the MCP protocol version; empty means negotiate
+	ProtocolVersion string `json:"protocol_version,omitempty"`
+	// Roots are the paths or URIs exposed to the server; empty means the
+	// current directory
+	Roots []string `json:"roots,omitempty"`
+	// Sampling selects how the server's sampling requests are answered
+	Sampling *SamplingConfig `json:"sampling,omitempty"`
+}
+
+// SamplingConfig selects a handler for sampling/createMessage. Exactly one
+// of Command, Fixture and BaseURL should be set.
+type SamplingConfig struct {
+	// Command is run per request with the request JSON on stdin and the
+	// result on stdout, e.g. "python3 sampler.py". Arguments are split
+	// like a shell does, so quote paths with spaces.
+	Command string `json:"command,omitempty"`
+	// Fixture is a JSON file of scripted responses
+	Fixture string `json:"fixture,omitempty"`
+	// BaseURL is an OpenAI-compatible API, e.g. "http://localhost:11434/v1"
+	BaseURL string `json:"base_url,omitempty"`
+	// Model is sent to BaseURL; empty means the server's first model hint
+	Model string `json:"model,omitempty"`
+	// APIKeyEnv names the environment variable holding the API key for
+	// BaseURL (default: OPENAI_API_KEY)
+	APIKeyEnv string `json:"api_key_env,omitempty"`
+	// AutoApprove skips the approval prompts in interactive mode
+	AutoApprove bool `json:"auto_approve,omitempty"`
+}
+
+// RequestTimeout parses the per-request timeout, e.g. "30s" or "2m".
+// An empty Timeout gives zero too; check it to tell the server having no
+// timeout of its own from "0", which disables the timeout.
+func (s ServerConfig) RequestTimeout() (time.Duration, error) {
+	if s.Timeout == "" {
+		return 0, nil
+	}
+
+	d, err := time.ParseDuration(s.Timeout)
+	if err != nil {
+		return 0, fmt.Errorf("invalid timeout %q: %w", s.Timeout, err)
+	}
+	return d, nil
 }
 
 type Config struct {
@@ -0,0 +1,81 @@
+package jsonschema
+
+import (
+	"net"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/jkeresman01/mcp-client/uritemplate"
+)
+
+var (
+	durationPattern    = regexp.MustCompile(`^P(?:\d+W|(?:\d+Y)?(?:\d+M)?(?:\d+D)?(?:T(?:\d+H)?(?:\d+M)?(?:\d+(?:\.\d+)?S)?)?)$`)
+	uuidPattern        = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	hostnameLabel      = regexp.MustCompile(`^[A-Za-z0-9](?:[A-Za-z0-9-]{0,61}[A-Za-z0-9])?$`)
+	jsonPointerPattern = regexp.MustCompile(`^(?:/(?:[^~/]|~[01])*)*$`)
+	relPointerPattern  = regexp.MustCompile(`^(?:0|[1-9][0-9]*)(?:#|(?:/(?:[^~/]|~[01])*)*)$`)
+)
+
+// checkFormat reports whether v is valid for a format from draft 2020-12.
+// Formats it doesn't know pass.
+func checkFormat(format, v string) bool {
+	switch format {
+	case "date-time":
+		_, err := time.Parse(time.RFC3339Nano, strings.ToUpper(v))
+		return err == nil
+	case "date":
+		_, err := time.Parse("2006-01-02", v)
+		return err == nil
+	case "time":
+		_, err := time.Parse("15:04:05.999999999Z07:00", strings.ToUpper(v))
+		return err == nil
+	case "duration":
+		return v != "P" && !strings.HasSuffix(v, "T") && durationPattern.MatchString(v)
+	case "email", "idn-email":
+		addr, err := mail.ParseAddress(v)
+		return err == nil && addr.Address == v
+	case "hostname":
+		return validHostname(v)
+	case "ipv4":
+		ip := net.ParseIP(v)
+		return ip != nil && !strings.Contains(v, ":")
+	case "ipv6":
+		ip := net.ParseIP(v)
+		return ip != nil && strings.Contains(v, ":")
+	case "uri", "iri":
+		u, err := url.Parse(v)
+		return err == nil && u.IsAbs()
+	case "uri-reference", "iri-reference":
+		_, err := url.Parse(v)
+		return err == nil
+	case "uri-template":
+		_, err := uritemplate.Parse(v)
+		return err == nil
+	case "uuid":
+		return uuidPattern.MatchString(v)
+	case "regex":
+		_, err := regexp.Compile(v)
+		return err == nil
+	case "json-pointer":
+		return jsonPointerPattern.MatchString(v)
+	case "relative-json-pointer":
+		return relPointerPattern.MatchString(v)
+	}
+	return true
+}
+
+func validHostname(v string) bool {
+	v = strings.TrimSuffix(v, ".")
+	if v == "" || len(v) > 253 {
+		return false
+	}
+	for _, label := range strings.Split(v, ".") {
+		if !hostnameLabel.MatchString(label) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,347 @@
+// Package jsonschema validates JSON values against JSON Schema draft
+// 2020-12, which MCP servers use to describe tool inputs and outputs.
+// Schemas written for draft-07 (definitions, array-form items,
+// dependencies) are understood as well. Only references within the schema
+// document are resolved; nothing is fetched over the network.
+package jsonschema
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// defaultBase is the base URI of a schema without an $id
+const defaultBase = "mcp-client:///schema.json"
+
+// maxDepth stops $ref cycles that never consume any of the instance
+const maxDepth = 256
+
+// Schema is a compiled schema, ready to validate instances
+type Schema struct {
+	// AssertFormats makes "format" an assertion for the formats this
+	// package knows, instead of the annotation draft 2020-12 treats it as
+	AssertFormats bool
+
+	root      *resource
+	resources map[string]*resource
+	patterns  map[string]*regexp.Regexp
+
+	// bases holds the base URI of every schema object by its map pointer,
+	// for lookups that start at a subschema
+	bases map[uintptr]string
+}
+
+// resource is a schema with its own base URI: the root, or any subschema
+// with an $id
+type resource struct {
+	uri            string
+	schema         interface{}
+	anchors        map[string]interface{}
+	dynamicAnchors map[string]bool
+}
+
+// Error is one failed assertion, at the instance location it applies to
+type Error struct {
+	// Path is a JSON path into the instance, e.g. $.filter.tags[0]
+	Path string
+	// Keyword is the schema keyword that failed, e.g. "required"
+	Keyword string
+	Message string
+}
+
+func (e Error) String() string {
+	return e.Path + ": " + e.Message
+}
+
+// ValidationError lists everything wrong with an instance
+type ValidationError struct {
+	Errors []Error
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		messages[i] = err.String()
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Compile indexes a decoded JSON schema (a map or a bool) and checks that
+// its patterns and references can be used
+func Compile(schema interface{}) (*Schema, error) {
+	switch schema.(type) {
+	case bool, map[string]interface{}:
+	default:
+		return nil, fmt.Errorf("a schema must be an object or a boolean, got %s", typeOf(schema))
+	}
+
+	s := &Schema{
+		resources: map[string]*resource{},
+		patterns:  map[string]*regexp.Regexp{},
+		bases:     map[uintptr]string{},
+	}
+
+	var refs []ref
+	root, err := s.index(schema, nil, defaultBase, &refs)
+	if err != nil {
+		return nil, err
+	}
+	s.root = root
+
+	for _, r := range refs {
+		if _, _, err := s.lookup(r.base, r.ref); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// ref is a $ref or $dynamicRef seen while indexing, checked once every
+// resource is known
+type ref struct {
+	base string
+	ref  string
+}
+
+// index walks the subschemas of node, registering resources and anchors
+// and compiling patterns. It returns the resource of node when node starts
+// one.
+func (s *Schema) index(node interface{}, res *resource, base string, refs *[]ref) (*resource, error) {
+	m, ok := node.(map[string]interface{})
+	if !ok {
+		if res == nil {
+			res = &resource{uri: base, schema: node}
+			s.resources[base] = res
+		}
+		return res, nil
+	}
+
+	if id, ok := m["$id"].(string); ok {
+		uri, _, err := resolve(base, id)
+		if err != nil {
+			return nil, fmt.Errorf("invalid $id %q: %v", id, err)
+		}
+		res = &resource{uri: uri, schema: node}
+		s.resources[uri] = res
+		base = uri
+	} else if res == nil {
+		res = &resource{uri: base, schema: node}
+		s.resources[base] = res
+	}
+	s.bases[nodeKey(m)] = base
+
+	if anchor, ok := m["$anchor"].(string); ok {
+		res.anchor(anchor, node, false)
+	}
+	if anchor, ok := m["$dynamicAnchor"].(string); ok {
+		res.anchor(anchor, node, true)
+	}
+	for _, keyword := range []string{"$ref", "$dynamicRef"} {
+		if r, ok := m[keyword].(string); ok {
+			*refs = append(*refs, ref{base: base, ref: r})
+		}
+	}
+
+	if pattern, ok := m["pattern"].(string); ok {
+		if err := s.compilePattern(pattern); err != nil {
+			return nil, err
+		}
+	}
+	if patterns, ok := m["patternProperties"].(map[string]interface{}); ok {
+		for pattern := range patterns {
+			if err := s.compilePattern(pattern); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for _, sub := range subschemas(m) {
+		if _, err := s.index(sub, res, base, refs); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// nodeKey identifies a schema object
+func nodeKey(m map[string]interface{}) uintptr {
+	return reflect.ValueOf(m).Pointer()
+}
+
+// baseOf returns the base URI that references in node resolve against
+func (s *Schema) baseOf(node interface{}) string {
+	if m, ok := node.(map[string]interface{}); ok {
+		if base, ok := s.bases[nodeKey(m)]; ok {
+			return base
+		}
+	}
+	return s.root.uri
+}
+
+func (r *resource) anchor(name string, node interface{}, dynamic bool) {
+	if r.anchors == nil {
+		r.anchors = map[string]interface{}{}
+		r.dynamicAnchors = map[string]bool{}
+	}
+	r.anchors[name] = node
+	if dynamic {
+		r.dynamicAnchors[name] = true
+	}
+}
+
+func (s *Schema) compilePattern(pattern string) error {
+	if _, ok := s.patterns[pattern]; ok {
+		return nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return fmt.Errorf("unsupported pattern %q: %v", pattern, err)
+	}
+	s.patterns[pattern] = re
+	return nil
+}
+
+// Keywords whose value is a schema, a map of schemas or a list of schemas
+var (
+	schemaKeywords = []string{
+		"additionalProperties", "propertyNames", "contains", "not", "if", "then", "else",
+		"unevaluatedItems", "unevaluatedProperties", "additionalItems", "contentSchema", "items",
+	}
+	schemaMapKeywords  = []string{"properties", "patternProperties", "$defs", "definitions", "dependentSchemas", "dependencies"}
+	schemaListKeywords = []string{"allOf", "anyOf", "oneOf", "prefixItems", "items"}
+)
+
+// subschemas returns the schemas nested directly in m, in a stable order
+func subschemas(m map[string]interface{}) []interface{} {
+	var subs []interface{}
+
+	for _, keyword := range schemaKeywords {
+		if sub, ok := m[keyword]; ok && isSchema(sub) {
+			subs = append(subs, sub)
+		}
+	}
+	for _, keyword := range schemaMapKeywords {
+		values, _ := m[keyword].(map[string]interface{})
+		for _, key := range sortedKeys(values) {
+			if isSchema(values[key]) {
+				subs = append(subs, values[key])
+			}
+		}
+	}
+	for _, keyword := range schemaListKeywords {
+		values, _ := m[keyword].([]interface{})
+		for _, sub := range values {
+			if isSchema(sub) {
+				subs = append(subs, sub)
+			}
+		}
+	}
+	return subs
+}
+
+func isSchema(v interface{}) bool {
+	switch v.(type) {
+	case bool, map[string]interface{}:
+		return true
+	}
+	return false
+}
+
+// resolve resolves ref against base, returning the absolute URI without
+// its fragment, and the unescaped fragment
+func resolve(base, ref string) (string, string, error) {
+	b, err := url.Parse(base)
+	if err != nil {
+		return "", "", err
+	}
+	r, err := url.Parse(ref)
+	if err != nil {
+		return "", "", err
+	}
+
+	u := b.ResolveReference(r)
+	if b.Opaque != "" && r.Scheme == "" && r.Opaque == "" && r.Path == "" {
+		// ResolveReference drops the opaque part of URNs for "#fragment"
+		u = &url.URL{Scheme: b.Scheme, Opaque: b.Opaque, Fragment: r.Fragment}
+	}
+
+	fragment := u.Fragment
+	u.Fragment = ""
+	u.RawFragment = ""
+	return u.String(), fragment, nil
+}
+
+// lookup finds the schema a reference points to, and the resource it
+// belongs to
+func (s *Schema) lookup(base, ref string) (interface{}, *resource, error) {
+	uri, fragment, err := resolve(base, ref)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid reference %q: %v", ref, err)
+	}
+
+	res, ok := s.resources[uri]
+	if !ok {
+		return nil, nil, fmt.Errorf("can't resolve reference %q: only references within the schema are supported", ref)
+	}
+
+	if fragment == "" {
+		return res.schema, res, nil
+	}
+	if strings.HasPrefix(fragment, "/") {
+		return s.pointer(res, fragment, ref)
+	}
+	if node, ok := res.anchors[fragment]; ok {
+		return node, res, nil
+	}
+	return nil, nil, fmt.Errorf("can't resolve reference %q: no anchor %q", ref, fragment)
+}
+
+// pointer follows a JSON pointer from the root of res. Subschemas with an
+// $id on the way change the resource the target belongs to.
+func (s *Schema) pointer(res *resource, pointer, ref string) (interface{}, *resource, error) {
+	node := res.schema
+
+	for _, token := range strings.Split(pointer[1:], "/") {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+
+		switch n := node.(type) {
+		case map[string]interface{}:
+			next, ok := n[token]
+			if !ok {
+				return nil, nil, fmt.Errorf("can't resolve reference %q: no %q", ref, token)
+			}
+			node = next
+		case []interface{}:
+			i, err := strconv.Atoi(token)
+			if err != nil || i < 0 || i >= len(n) {
+				return nil, nil, fmt.Errorf("can't resolve reference %q: no item %q", ref, token)
+			}
+			node = n[i]
+		default:
+			return nil, nil, fmt.Errorf("can't resolve reference %q", ref)
+		}
+
+		if m, ok := node.(map[string]interface{}); ok {
+			if id, ok := m["$id"].(string); ok {
+				if uri, _, err := resolve(res.uri, id); err == nil && s.resources[uri] != nil {
+					res = s.resources[uri]
+				}
+			}
+		}
+	}
+	return node, res, nil
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,336 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// suiteGroup is a schema with test instances, in the format of the
+// JSON-Schema-Test-Suite, which testdata holds a subset of
+type suiteGroup struct {
+	Description string          `json:"description"`
+	Schema      json.RawMessage `json:"schema"`
+	Tests       []struct {
+		Description string          `json:"description"`
+		Data        json.RawMessage `json:"data"`
+		Valid       bool            `json:"valid"`
+	} `json:"tests"`
+}
+
+func TestSuite(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join("testdata", "draft2020-12", "*.json"))
+	if err != nil || len(files) == 0 {
+		t.Fatalf("no test files: %v", err)
+	}
+
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var groups []suiteGroup
+		if err := json.Unmarshal(data, &groups); err != nil {
+			t.Fatalf("%s: %v", file, err)
+		}
+
+		name := strings.TrimSuffix(filepath.Base(file), ".json")
+		t.Run(name, func(t *testing.T) {
+			for _, g := range groups {
+				schema, err := Compile(decode(t, g.Schema))
+				if err != nil {
+					t.Errorf("%s: Compile: %v", g.Description, err)
+					continue
+				}
+				// Formats are only asserted on request
+				schema.AssertFormats = name == "format"
+
+				for _, tt := range g.Tests {
+					err := schema.Validate(decode(t, tt.Data))
+					if valid := err == nil; valid != tt.Valid {
+						t.Errorf("%s / %s: valid = %v, want %v (%v)", g.Description, tt.Description, valid, tt.Valid, err)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestFormatsAreAnnotationsByDefault(t *testing.T) {
+	schema := mustCompile(t, `{"format": "email"}`)
+	if err := schema.Validate("not an address"); err != nil {
+		t.Errorf("Validate() = %v, want nil without AssertFormats", err)
+	}
+
+	schema.AssertFormats = true
+	if err := schema.Validate("not an address"); err == nil {
+		t.Error("Validate() = nil, want an error with AssertFormats")
+	}
+}
+
+func TestValidationErrors(t *testing.T) {
+	schema := mustCompile(t, `{
+		"type": "object",
+		"properties": {
+			"query": {"type": "string", "minLength": 1},
+			"limit": {"type": "integer", "minimum": 1, "maximum": 100},
+			"tags": {"type": "array", "items": {"type": "string"}, "uniqueItems": true},
+			"sort": {"enum": ["relevance", "date"]},
+			"my key": {"type": "boolean"}
+		},
+		"required": ["query"],
+		"additionalProperties": false
+	}`)
+
+	tests := []struct {
+		instance string
+		want     []Error
+	}{
+		{`{"query": "go"}`, nil},
+		{`{}`, []Error{
+			{Path: "$.query", Keyword: "required", Message: "is required"},
+		}},
+		{`{"query": "", "limit": 0}`, []Error{
+			{Path: "$.limit", Keyword: "minimum", Message: "must be >= 1"},
+			{Path: "$.query", Keyword: "minLength", Message: "must be at least 1 character"},
+		}},
+		{`{"query": "go", "limit": 2.5}`, []Error{
+			{Path: "$.limit", Keyword: "type", Message: "expected integer, got number"},
+		}},
+		{`{"query": "go", "tags": ["a", 1, "a"]}`, []Error{
+			{Path: "$.tags", Keyword: "uniqueItems", Message: "items 0 and 2 are equal"},
+			{Path: "$.tags[1]", Keyword: "type", Message: "expected string, got integer"},
+		}},
+		{`{"query": "go", "sort": "name"}`, []Error{
+			{Path: "$.sort", Keyword: "enum", Message: `must be one of "relevance", "date"`},
+		}},
+		{`{"query": "go", "limt": 5}`, []Error{
+			{Path: "$.limt", Keyword: "additionalProperties", Message: `is not allowed (did you mean "limit"?)`},
+		}},
+		{`{"query": "go", "colour": "red"}`, []Error{
+			{Path: "$.colour", Keyword: "additionalProperties", Message: "is not allowed"},
+		}},
+		{`{"query": "go", "my key": "yes"}`, []Error{
+			{Path: `$["my key"]`, Keyword: "type", Message: "expected boolean, got string"},
+		}},
+		{`[]`, []Error{
+			{Path: "$", Keyword: "type", Message: "expected object, got array"},
+		}},
+	}
+
+	for _, tt := range tests {
+		err := schema.Validate(decode(t, json.RawMessage(tt.instance)))
+		if got := validationErrors(t, err); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Validate(%s) = %v, want %v", tt.instance, got, tt.want)
+		}
+	}
+}
+
+func TestClosestBranch(t *testing.T) {
+	schema := mustCompile(t, `{"oneOf": [
+		{"type": "string"},
+		{"type": "object", "properties": {"n": {"type": "integer"}}, "required": ["n"]}
+	]}`)
+
+	// Only the object branch fits an object, so its errors are reported
+	got := validationErrors(t, schema.Validate(map[string]interface{}{"n": "x"}))
+	want := []Error{{Path: "$.n", Keyword: "type", Message: "expected integer, got string"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Validate() = %v, want %v", got, want)
+	}
+
+	got = validationErrors(t, schema.Validate(true))
+	want = []Error{{Path: "$", Keyword: "oneOf", Message: "does not match any schema in oneOf"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Validate() = %v, want %v", got, want)
+	}
+}
+
+func TestValidateAt(t *testing.T) {
+	schema := mustCompile(t, `{
+		"properties": {"filter": {"$ref": "#/$defs/filter"}},
+		"$defs": {"filter": {"properties": {"since": {"type": "string"}}, "required": ["since"]}}
+	}`)
+
+	node := schema.Property(schema.Root(), "filter")
+	got := validationErrors(t, schema.ValidateAt(node, map[string]interface{}{}, "$.filter"))
+	want := []Error{{Path: "$.filter.since", Keyword: "required", Message: "is required"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ValidateAt() = %v, want %v", got, want)
+	}
+}
+
+func TestRecursiveRefTerminates(t *testing.T) {
+	schema := mustCompile(t, `{"$defs": {"a": {"$ref": "#/$defs/b"}, "b": {"$ref": "#/$defs/a"}}, "$ref": "#/$defs/a"}`)
+
+	got := validationErrors(t, schema.Validate(1))
+	if len(got) != 1 || got[0].Keyword != "$ref" {
+		t.Errorf("Validate() = %v, want one $ref error", got)
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	tests := []struct {
+		schema string
+		want   string
+	}{
+		{`"string"`, "a schema must be an object or a boolean, got string"},
+		{`{"pattern": "(?<=a)b"}`, "unsupported pattern"},
+		{`{"patternProperties": {"[": {}}}`, "unsupported pattern"},
+		{`{"$ref": "#/$defs/missing"}`, `can't resolve reference "#/$defs/missing"`},
+		{`{"$ref": "#nowhere"}`, `no anchor "nowhere"`},
+		{`{"$ref": "https://example.com/schema.json"}`, "only references within the schema are supported"},
+	}
+
+	for _, tt := range tests {
+		_, err := Compile(decode(t, json.RawMessage(tt.schema)))
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Compile(%s) error = %v, want it to contain %q", tt.schema, err, tt.want)
+		}
+	}
+}
+
+func TestLookups(t *testing.T) {
+	schema := mustCompile(t, `{
+		"type": "object",
+		"properties": {
+			"query": {"type": "string", "description": "What to look for"},
+			"filter": {"$ref": "#/$defs/filter"},
+			"pair": {"prefixItems": [{"type": "integer"}], "items": {"type": "string"}},
+			"mode": {"enum": ["a", "b"]},
+			"level": {"const": 3}
+		},
+		"patternProperties": {"^x-": {"type": "boolean"}},
+		"allOf": [{"properties": {"extra": {"type": ["number", "null"]}}, "required": ["extra"]}],
+		"anyOf": [{"required": ["query"]}, {"required": ["filter"]}],
+		"required": ["mode"],
+		"$defs": {"filter": {"type": "object", "properties": {"since": {"type": "string", "default": "today"}}}}
+	}`)
+	root := schema.Root()
+
+	if got, want := schema.Properties(root), []string{"extra", "filter", "level", "mode", "pair", "query"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Properties() = %v, want %v", got, want)
+	}
+	if got, want := schema.Required(root), []string{"mode", "extra"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Required() = %v, want %v", got, want)
+	}
+
+	filter := schema.Property(root, "filter")
+	if got, want := schema.Types(filter), []string{"object"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Types(filter) = %v, want %v", got, want)
+	}
+	if got, _ := schema.Keyword(schema.Property(filter, "since"), "default"); got != "today" {
+		t.Errorf("Keyword(since, default) = %v, want today", got)
+	}
+	if got, _ := schema.Keyword(schema.Property(root, "query"), "description"); got != "What to look for" {
+		t.Errorf("Keyword(query, description) = %v", got)
+	}
+
+	if got, want := schema.Types(schema.Property(root, "extra")), []string{"number", "null"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Types(extra) = %v, want %v", got, want)
+	}
+	if got, want := schema.Types(schema.Property(root, "x-debug")), []string{"boolean"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Types(x-debug) = %v, want %v", got, want)
+	}
+	if got := schema.Property(root, "unknown"); got != nil {
+		t.Errorf("Property(unknown) = %v, want nil", got)
+	}
+
+	mode := schema.Property(root, "mode")
+	if got, want := schema.Enum(mode), []interface{}{"a", "b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Enum(mode) = %v, want %v", got, want)
+	}
+	if got, want := schema.Types(mode), []string{"string"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Types(mode) = %v, want %v", got, want)
+	}
+	if got, want := schema.Types(schema.Property(root, "level")), []string{"integer"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Types(level) = %v, want %v", got, want)
+	}
+
+	pair := schema.Property(root, "pair")
+	if got, want := schema.Types(schema.Item(pair, 0)), []string{"integer"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Types(pair[0]) = %v, want %v", got, want)
+	}
+	if got, want := schema.Types(schema.Item(pair, 5)), []string{"string"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Types(pair[5]) = %v, want %v", got, want)
+	}
+}
+
+func TestLookupsResolveAgainstNestedIDs(t *testing.T) {
+	schema := mustCompile(t, `{
+		"$id": "https://example.com/root.json",
+		"properties": {
+			"item": {"$ref": "item.json"},
+			"inline": {"allOf": [{"$id": "inline.json", "$ref": "#/$defs/kind", "$defs": {"kind": {"type": "boolean"}}}]}
+		},
+		"$defs": {
+			"kind": {"type": "integer"},
+			"item": {
+				"$id": "item.json",
+				"properties": {"kind": {"$ref": "#/$defs/kind"}},
+				"$defs": {"kind": {"type": "string"}}
+			}
+		}
+	}`)
+	root := schema.Root()
+
+	kind := schema.Property(schema.Property(root, "item"), "kind")
+	if got, want := schema.Types(kind), []string{"string"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Types(item.kind) = %v, want %v", got, want)
+	}
+	if got, want := schema.Types(schema.Property(root, "inline")), []string{"boolean"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Types(inline) = %v, want %v", got, want)
+	}
+
+	got := validationErrors(t, schema.ValidateAt(kind, 1.0, "$.item.kind"))
+	want := []Error{{Path: "$.item.kind", Keyword: "type", Message: "expected string, got integer"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ValidateAt() = %v, want %v", got, want)
+	}
+}
+
+func TestLookupsVisitEachSchemaOnce(t *testing.T) {
+	// Without remembering visited schemas this fans out 2^depth times
+	schema := mustCompile(t, `{
+		"$defs": {"a": {"anyOf": [{"$ref": "#/$defs/a"}, {"$ref": "#/$defs/a"}, {"type": "string"}]}},
+		"$ref": "#/$defs/a"
+	}`)
+
+	if got, want := schema.Types(schema.Root()), []string{"string"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Types() = %v, want %v", got, want)
+	}
+}
+
+func mustCompile(t *testing.T, schema string) *Schema {
+	t.Helper()
+	s, err := Compile(decode(t, json.RawMessage(schema)))
+	if err != nil {
+		t.Fatalf("Compile: %v", err)
+	}
+	return s
+}
+
+func decode(t *testing.T, data json.RawMessage) interface{} {
+	t.Helper()
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		t.Fatalf("decode %s: %v", data, err)
+	}
+	return v
+}
+
+func validationErrors(t *testing.T, err error) []Error {
+	t.Helper()
+	if err == nil {
+		return nil
+	}
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("error %v is not a *ValidationError", err)
+	}
+	return verr.Errors
+}
@@ -0,0 +1,198 @@
+package jsonschema
+
+import "sort"
+
+// Lookups let callers build instances from a schema, e.g. to convert
+// command line strings to the types a schema declares. They follow $ref
+// and look into allOf, anyOf and oneOf, and resolve references against the
+// base URI of the $id resource they are in.
+
+// inPlace are the applicators whose subschemas apply to the same instance
+var inPlace = []string{"allOf", "anyOf", "oneOf"}
+
+// Root returns the schema that was compiled
+func (s *Schema) Root() interface{} {
+	return s.root.schema
+}
+
+// Property returns the subschema that applies to property name of objects
+// matching node, from properties, patternProperties or
+// additionalProperties, or nil if the schema doesn't say
+func (s *Schema) Property(node interface{}, name string) interface{} {
+	branches := s.branches(node, inPlace...)
+
+	for _, m := range branches {
+		properties, _ := m["properties"].(map[string]interface{})
+		if sub, ok := properties[name]; ok {
+			return sub
+		}
+	}
+	for _, m := range branches {
+		patterns, _ := m["patternProperties"].(map[string]interface{})
+		for _, pattern := range sortedKeys(patterns) {
+			if re := s.patterns[pattern]; re != nil && re.MatchString(name) {
+				return patterns[pattern]
+			}
+		}
+	}
+	for _, m := range branches {
+		if sub, ok := m["additionalProperties"]; ok && isSchema(sub) {
+			return sub
+		}
+	}
+	return nil
+}
+
+// Properties lists the declared property names of objects matching node
+func (s *Schema) Properties(node interface{}) []string {
+	seen := map[string]bool{}
+	var names []string
+
+	for _, m := range s.branches(node, inPlace...) {
+		properties, _ := m["properties"].(map[string]interface{})
+		for name := range properties {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Required lists the properties objects matching node must have
+func (s *Schema) Required(node interface{}) []string {
+	seen := map[string]bool{}
+	var names []string
+
+	// A property required by one anyOf or oneOf branch isn't required
+	for _, m := range s.branches(node, "allOf") {
+		required, _ := m["required"].([]interface{})
+		for _, name := range required {
+			if name, ok := name.(string); ok && !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	return names
+}
+
+// Keyword returns the first value of an annotation keyword such as
+// "description" or "default" on node or the schemas it pulls in
+func (s *Schema) Keyword(node interface{}, keyword string) (interface{}, bool) {
+	for _, m := range s.branches(node, inPlace...) {
+		if v, ok := m[keyword]; ok {
+			return v, true
+		}
+	}
+	return nil, false
+}
+
+// Item returns the subschema that applies to item i of arrays matching
+// node, or nil if the schema doesn't say
+func (s *Schema) Item(node interface{}, i int) interface{} {
+	for _, m := range s.branches(node, inPlace...) {
+		prefix, _ := m["prefixItems"].([]interface{})
+		rest, hasRest := m["items"]
+		if list, ok := rest.([]interface{}); ok {
+			prefix = list
+			rest, hasRest = m["additionalItems"]
+		}
+
+		if i < len(prefix) {
+			return prefix[i]
+		}
+		if hasRest && isSchema(rest) {
+			return rest
+		}
+	}
+	return nil
+}
+
+// Types lists the JSON types node allows, in the order declared. Without a
+// "type" keyword the types of its enum or const values are used.
+func (s *Schema) Types(node interface{}) []string {
+	seen := map[string]bool{}
+	var types []string
+	add := func(t string) {
+		if !seen[t] {
+			seen[t] = true
+			types = append(types, t)
+		}
+	}
+
+	for _, m := range s.branches(node, inPlace...) {
+		switch t := m["type"].(type) {
+		case string:
+			add(t)
+		case []interface{}:
+			for _, name := range t {
+				if name, ok := name.(string); ok {
+					add(name)
+				}
+			}
+		}
+	}
+	if len(types) > 0 {
+		return types
+	}
+
+	for _, v := range s.Enum(node) {
+		add(typeOf(v))
+	}
+	return types
+}
+
+// Enum lists the values node allows through enum or const, if it limits
+// them
+func (s *Schema) Enum(node interface{}) []interface{} {
+	var values []interface{}
+	for _, m := range s.branches(node, inPlace...) {
+		if enum, ok := m["enum"].([]interface{}); ok {
+			values = append(values, enum...)
+		}
+		if c, ok := m["const"]; ok {
+			values = append(values, c)
+		}
+	}
+	return values
+}
+
+// branches returns node and every schema it pulls in through $ref and the
+// given applicators, each once
+func (s *Schema) branches(node interface{}, applicators ...string) []map[string]interface{} {
+	var out []map[string]interface{}
+	seen := map[uintptr]bool{}
+	var walk func(n interface{}, base string, depth int)
+
+	walk = func(n interface{}, base string, depth int) {
+		m, ok := n.(map[string]interface{})
+		if !ok || depth > maxDepth || seen[nodeKey(m)] {
+			return
+		}
+		seen[nodeKey(m)] = true
+		out = append(out, m)
+
+		if id, ok := m["$id"].(string); ok {
+			if uri, _, err := resolve(base, id); err == nil && s.resources[uri] != nil {
+				base = uri
+			}
+		}
+		if ref, ok := m["$ref"].(string); ok {
+			if target, res, err := s.lookup(base, ref); err == nil {
+				walk(target, res.uri, depth+1)
+			}
+		}
+		for _, keyword := range applicators {
+			subs, _ := m[keyword].([]interface{})
+			for _, sub := range subs {
+				walk(sub, base, depth+1)
+			}
+		}
+	}
+
+	walk(node, s.baseOf(node), 0)
+	return out
+}
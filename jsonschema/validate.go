@@ -0,0 +1,781 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Validate checks instance, a decoded JSON value, against the schema. It
+// returns a *ValidationError listing every failed assertion.
+func (s *Schema) Validate(instance interface{}) error {
+	return s.ValidateAt(s.root.schema, instance, "$")
+}
+
+// ValidateAt checks instance against node, a subschema returned by the
+// lookups, with errors reported under path. References resolve against
+// the resource node is in.
+func (s *Schema) ValidateAt(node, instance interface{}, path string) error {
+	sc := scope{res: s.root, dynamic: []*resource{s.root}}
+	if res := s.resources[s.baseOf(node)]; res != nil {
+		sc = sc.enter(res)
+	}
+
+	r := s.eval(node, instance, path, sc, 0)
+	if r.valid() {
+		return nil
+	}
+
+	sort.SliceStable(r.errs, func(i, j int) bool { return r.errs[i].Path < r.errs[j].Path })
+	return &ValidationError{Errors: r.errs}
+}
+
+// scope is where evaluation is in the schema: the resource that relative
+// references resolve against, and the resources entered on the way there,
+// outermost first, for $dynamicRef
+type scope struct {
+	res     *resource
+	dynamic []*resource
+}
+
+func (sc scope) enter(res *resource) scope {
+	if res == sc.res {
+		return sc
+	}
+	return scope{res: res, dynamic: append(sc.dynamic[:len(sc.dynamic):len(sc.dynamic)], res)}
+}
+
+// result is the outcome of evaluating one schema: its errors, and the
+// properties and items it evaluated, for unevaluatedProperties and
+// unevaluatedItems
+type result struct {
+	errs     []Error
+	props    map[string]bool
+	items    map[int]bool
+	allItems bool
+}
+
+func (r result) valid() bool {
+	return len(r.errs) == 0
+}
+
+func (r *result) fail(path, keyword, format string, args ...interface{}) {
+	r.errs = append(r.errs, Error{Path: path, Keyword: keyword, Message: fmt.Sprintf(format, args...)})
+}
+
+func (r *result) markProp(name string) {
+	if r.props == nil {
+		r.props = map[string]bool{}
+	}
+	r.props[name] = true
+}
+
+func (r *result) markItem(i int) {
+	if r.items == nil {
+		r.items = map[int]bool{}
+	}
+	r.items[i] = true
+}
+
+// merge takes the errors and annotations of a subschema applied to the
+// same instance
+func (r *result) merge(o result) {
+	r.errs = append(r.errs, o.errs...)
+	r.annotate(o)
+}
+
+// annotate takes only the annotations of o, for applicators like anyOf
+// that report errors themselves
+func (r *result) annotate(o result) {
+	for name := range o.props {
+		r.markProp(name)
+	}
+	for i := range o.items {
+		r.markItem(i)
+	}
+	r.allItems = r.allItems || o.allItems
+}
+
+func (s *Schema) eval(node, inst interface{}, path string, sc scope, depth int) result {
+	var r result
+
+	if depth > maxDepth {
+		r.fail(path, "$ref", "schema references nest too deeply")
+		return r
+	}
+
+	m, ok := node.(map[string]interface{})
+	if !ok {
+		if b, ok := node.(bool); ok && !b {
+			r.fail(path, "false", "is not allowed")
+		}
+		return r
+	}
+
+	if id, ok := m["$id"].(string); ok {
+		if uri, _, err := resolve(sc.res.uri, id); err == nil && s.resources[uri] != nil {
+			sc = sc.enter(s.resources[uri])
+		}
+	}
+
+	if ref, ok := m["$ref"].(string); ok {
+		target, res, err := s.lookup(sc.res.uri, ref)
+		if err != nil {
+			r.fail(path, "$ref", "%v", err)
+		} else {
+			r.merge(s.eval(target, inst, path, sc.enter(res), depth+1))
+		}
+	}
+	if ref, ok := m["$dynamicRef"].(string); ok {
+		target, res, err := s.dynamicLookup(sc, ref)
+		if err != nil {
+			r.fail(path, "$dynamicRef", "%v", err)
+		} else {
+			r.merge(s.eval(target, inst, path, sc.enter(res), depth+1))
+		}
+	}
+
+	s.evalGeneric(m, inst, path, &r)
+
+	switch v := inst.(type) {
+	case string:
+		s.evalString(m, v, path, &r)
+	case []interface{}:
+		s.evalArray(m, v, path, sc, depth, &r)
+	case map[string]interface{}:
+		s.evalObject(m, v, path, sc, depth, &r)
+	default:
+		if _, ok := number(inst); ok {
+			evalNumber(m, inst, path, &r)
+		}
+	}
+
+	s.evalApplicators(m, inst, path, sc, depth, &r)
+	s.evalUnevaluated(m, inst, path, sc, depth, &r)
+	return r
+}
+
+// dynamicLookup resolves $dynamicRef: like $ref, unless it lands on a
+// $dynamicAnchor, in which case the outermost resource in the dynamic scope
+// with the same $dynamicAnchor wins
+func (s *Schema) dynamicLookup(sc scope, ref string) (interface{}, *resource, error) {
+	target, res, err := s.lookup(sc.res.uri, ref)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	m, _ := target.(map[string]interface{})
+	name, _ := m["$dynamicAnchor"].(string)
+	_, fragment, _ := resolve(sc.res.uri, ref)
+	if name == "" || name != fragment {
+		return target, res, nil
+	}
+
+	for _, outer := range sc.dynamic {
+		if outer.dynamicAnchors[name] {
+			return outer.anchors[name], outer, nil
+		}
+	}
+	return target, res, nil
+}
+
+// evalGeneric checks the keywords that apply to every type
+func (s *Schema) evalGeneric(m map[string]interface{}, inst interface{}, path string, r *result) {
+	if t, ok := m["type"]; ok {
+		var types []string
+		switch t := t.(type) {
+		case string:
+			types = []string{t}
+		case []interface{}:
+			for _, name := range t {
+				if name, ok := name.(string); ok {
+					types = append(types, name)
+				}
+			}
+		}
+
+		matched := false
+		for _, name := range types {
+			if hasType(inst, name) {
+				matched = true
+				break
+			}
+		}
+		if !matched && len(types) > 0 {
+			r.fail(path, "type", "expected %s, got %s", strings.Join(types, " or "), typeOf(inst))
+		}
+	}
+
+	if values, ok := m["enum"].([]interface{}); ok {
+		found := false
+		for _, v := range values {
+			if equal(inst, v) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			r.fail(path, "enum", "must be one of %s", describeValues(values))
+		}
+	}
+
+	if c, ok := m["const"]; ok && !equal(inst, c) {
+		r.fail(path, "const", "must be %s", describeValue(c))
+	}
+}
+
+func evalNumber(m map[string]interface{}, inst interface{}, path string, r *result) {
+	x, _ := number(inst)
+
+	if limit, ok := number(m["minimum"]); ok && x < limit {
+		r.fail(path, "minimum", "must be >= %s", formatNumber(limit))
+	}
+	if limit, ok := number(m["exclusiveMinimum"]); ok && x <= limit {
+		r.fail(path, "exclusiveMinimum", "must be > %s", formatNumber(limit))
+	}
+	if limit, ok := number(m["maximum"]); ok && x > limit {
+		r.fail(path, "maximum", "must be <= %s", formatNumber(limit))
+	}
+	if limit, ok := number(m["exclusiveMaximum"]); ok && x >= limit {
+		r.fail(path, "exclusiveMaximum", "must be < %s", formatNumber(limit))
+	}
+	if divisor, ok := number(m["multipleOf"]); ok && divisor > 0 && !isMultiple(inst, m["multipleOf"]) {
+		r.fail(path, "multipleOf", "must be a multiple of %s", formatNumber(divisor))
+	}
+}
+
+func (s *Schema) evalString(m map[string]interface{}, v, path string, r *result) {
+	length := utf8.RuneCountInString(v)
+
+	if n, ok := count(m["minLength"]); ok && length < n {
+		r.fail(path, "minLength", "must be at least %s", plural(n, "character"))
+	}
+	if n, ok := count(m["maxLength"]); ok && length > n {
+		r.fail(path, "maxLength", "must be at most %s", plural(n, "character"))
+	}
+	if pattern, ok := m["pattern"].(string); ok && !s.patterns[pattern].MatchString(v) {
+		r.fail(path, "pattern", "must match pattern %q", pattern)
+	}
+	if format, ok := m["format"].(string); ok && s.AssertFormats && !checkFormat(format, v) {
+		r.fail(path, "format", "is not a valid %s", format)
+	}
+}
+
+func (s *Schema) evalArray(m map[string]interface{}, v []interface{}, path string, sc scope, depth int, r *result) {
+	if n, ok := count(m["minItems"]); ok && len(v) < n {
+		r.fail(path, "minItems", "must have at least %s", plural(n, "item"))
+	}
+	if n, ok := count(m["maxItems"]); ok && len(v) > n {
+		r.fail(path, "maxItems", "must have at most %s", plural(n, "item"))
+	}
+	if unique, _ := m["uniqueItems"].(bool); unique {
+	outer:
+		for i := range v {
+			for j := i + 1; j < len(v); j++ {
+				if equal(v[i], v[j]) {
+					r.fail(path, "uniqueItems", "items %d and %d are equal", i, j)
+					break outer
+				}
+			}
+		}
+	}
+
+	// Draft-07 spelled prefixItems as an array-form items, and items as
+	// additionalItems
+	prefix, _ := m["prefixItems"].([]interface{})
+	rest, hasRest := m["items"]
+	if list, ok := rest.([]interface{}); ok {
+		prefix = list
+		rest, hasRest = m["additionalItems"]
+	}
+
+	for i, sub := range prefix {
+		if i >= len(v) {
+			break
+		}
+		r.errs = append(r.errs, s.eval(sub, v[i], indexPath(path, i), sc, depth+1).errs...)
+		r.markItem(i)
+	}
+	if hasRest {
+		for i := len(prefix); i < len(v); i++ {
+			r.errs = append(r.errs, s.eval(rest, v[i], indexPath(path, i), sc, depth+1).errs...)
+		}
+		r.allItems = true
+	}
+
+	if contains, ok := m["contains"]; ok {
+		matched := 0
+		for i, item := range v {
+			if c := s.eval(contains, item, indexPath(path, i), sc, depth+1); c.valid() {
+				matched++
+				r.markItem(i)
+			}
+		}
+
+		least := 1
+		if n, ok := count(m["minContains"]); ok {
+			least = n
+		}
+		if matched < least {
+			r.fail(path, "contains", "must contain at least %s, found %d", plural(least, "matching item"), matched)
+		}
+		if most, ok := count(m["maxContains"]); ok && matched > most {
+			r.fail(path, "maxContains", "must contain at most %s, found %d", plural(most, "matching item"), matched)
+		}
+	}
+}
+
+func (s *Schema) evalObject(m map[string]interface{}, v map[string]interface{}, path string, sc scope, depth int, r *result) {
+	names := sortedKeys(v)
+
+	if n, ok := count(m["minProperties"]); ok && len(v) < n {
+		r.fail(path, "minProperties", "must have at least %s", plural(n, "property"))
+	}
+	if n, ok := count(m["maxProperties"]); ok && len(v) > n {
+		r.fail(path, "maxProperties", "must have at most %s", plural(n, "property"))
+	}
+
+	if required, ok := m["required"].([]interface{}); ok {
+		for _, name := range required {
+			if name, ok := name.(string); ok {
+				if _, present := v[name]; !present {
+					r.fail(propertyPath(path, name), "required", "is required")
+				}
+			}
+		}
+	}
+
+	dependentRequired, _ := m["dependentRequired"].(map[string]interface{})
+	dependentSchemas, _ := m["dependentSchemas"].(map[string]interface{})
+	if dependencies, ok := m["dependencies"].(map[string]interface{}); ok {
+		for name, dep := range dependencies {
+			if _, ok := dep.([]interface{}); ok {
+				dependentRequired = withEntry(dependentRequired, name, dep)
+			} else {
+				dependentSchemas = withEntry(dependentSchemas, name, dep)
+			}
+		}
+	}
+	for _, name := range sortedKeys(dependentRequired) {
+		if _, present := v[name]; !present {
+			continue
+		}
+		deps, _ := dependentRequired[name].([]interface{})
+		for _, dep := range deps {
+			if dep, ok := dep.(string); ok {
+				if _, present := v[dep]; !present {
+					r.fail(propertyPath(path, dep), "dependentRequired", "is required when %q is present", name)
+				}
+			}
+		}
+	}
+	for _, name := range sortedKeys(dependentSchemas) {
+		if _, present := v[name]; present {
+			r.merge(s.eval(dependentSchemas[name], v, path, sc, depth+1))
+		}
+	}
+
+	properties, _ := m["properties"].(map[string]interface{})
+	patterns, _ := m["patternProperties"].(map[string]interface{})
+	additional, hasAdditional := m["additionalProperties"]
+
+	for _, name := range names {
+		known := false
+
+		if sub, ok := properties[name]; ok {
+			known = true
+			r.errs = append(r.errs, s.eval(sub, v[name], propertyPath(path, name), sc, depth+1).errs...)
+			r.markProp(name)
+		}
+		for _, pattern := range sortedKeys(patterns) {
+			if s.patterns[pattern].MatchString(name) {
+				known = true
+				r.errs = append(r.errs, s.eval(patterns[pattern], v[name], propertyPath(path, name), sc, depth+1).errs...)
+				r.markProp(name)
+			}
+		}
+
+		if !known && hasAdditional {
+			if b, ok := additional.(bool); ok && !b {
+				r.fail(propertyPath(path, name), "additionalProperties", "%s", unknownProperty(name, properties))
+			} else {
+				r.errs = append(r.errs, s.eval(additional, v[name], propertyPath(path, name), sc, depth+1).errs...)
+			}
+			r.markProp(name)
+		}
+	}
+
+	if propertyNames, ok := m["propertyNames"]; ok {
+		for _, name := range names {
+			for _, err := range s.eval(propertyNames, name, propertyPath(path, name), sc, depth+1).errs {
+				err.Message = "property name " + err.Message
+				r.errs = append(r.errs, err)
+			}
+		}
+	}
+}
+
+// evalApplicators handles allOf, anyOf, oneOf, not and if/then/else
+func (s *Schema) evalApplicators(m map[string]interface{}, inst interface{}, path string, sc scope, depth int, r *result) {
+	if allOf, ok := m["allOf"].([]interface{}); ok {
+		for _, sub := range allOf {
+			r.merge(s.eval(sub, inst, path, sc, depth+1))
+		}
+	}
+
+	if anyOf, ok := m["anyOf"].([]interface{}); ok {
+		results := make([]result, len(anyOf))
+		matched := false
+		for i, sub := range anyOf {
+			results[i] = s.eval(sub, inst, path, sc, depth+1)
+			if results[i].valid() {
+				matched = true
+				r.annotate(results[i])
+			}
+		}
+		if !matched {
+			r.errs = append(r.errs, closestBranch(results, path, "anyOf")...)
+		}
+	}
+
+	if oneOf, ok := m["oneOf"].([]interface{}); ok {
+		results := make([]result, len(oneOf))
+		var matched []string
+		for i, sub := range oneOf {
+			results[i] = s.eval(sub, inst, path, sc, depth+1)
+			if results[i].valid() {
+				matched = append(matched, strconv.Itoa(i))
+			}
+		}
+		switch len(matched) {
+		case 0:
+			r.errs = append(r.errs, closestBranch(results, path, "oneOf")...)
+		case 1:
+			i, _ := strconv.Atoi(matched[0])
+			r.annotate(results[i])
+		default:
+			r.fail(path, "oneOf", "must match exactly one schema in oneOf, but matches %s", strings.Join(matched, ", "))
+		}
+	}
+
+	if not, ok := m["not"]; ok {
+		if s.eval(not, inst, path, sc, depth+1).valid() {
+			r.fail(path, "not", "must not match the schema in not")
+		}
+	}
+
+	if cond, ok := m["if"]; ok {
+		c := s.eval(cond, inst, path, sc, depth+1)
+		if c.valid() {
+			r.annotate(c)
+			if then, ok := m["then"]; ok {
+				r.merge(s.eval(then, inst, path, sc, depth+1))
+			}
+		} else if otherwise, ok := m["else"]; ok {
+			r.merge(s.eval(otherwise, inst, path, sc, depth+1))
+		}
+	}
+}
+
+// evalUnevaluated applies unevaluatedItems and unevaluatedProperties to
+// whatever no other keyword of this schema, or its in-place applicators,
+// looked at
+func (s *Schema) evalUnevaluated(m map[string]interface{}, inst interface{}, path string, sc scope, depth int, r *result) {
+	if sub, ok := m["unevaluatedItems"]; ok {
+		if v, ok := inst.([]interface{}); ok && !r.allItems {
+			for i := range v {
+				if r.items[i] {
+					continue
+				}
+				if b, ok := sub.(bool); ok && !b {
+					r.fail(indexPath(path, i), "unevaluatedItems", "is not allowed")
+				} else {
+					r.errs = append(r.errs, s.eval(sub, v[i], indexPath(path, i), sc, depth+1).errs...)
+				}
+			}
+			r.allItems = true
+		}
+	}
+
+	if sub, ok := m["unevaluatedProperties"]; ok {
+		if v, ok := inst.(map[string]interface{}); ok {
+			properties, _ := m["properties"].(map[string]interface{})
+			for _, name := range sortedKeys(v) {
+				if r.props[name] {
+					continue
+				}
+				if b, ok := sub.(bool); ok && !b {
+					r.fail(propertyPath(path, name), "unevaluatedProperties", "%s", unknownProperty(name, properties))
+				} else {
+					r.errs = append(r.errs, s.eval(sub, v[name], propertyPath(path, name), sc, depth+1).errs...)
+				}
+				r.markProp(name)
+			}
+		}
+	}
+}
+
+// closestBranch explains why no anyOf or oneOf schema matched. Branches
+// that fail on the type of the instance itself are ruled out; if that
+// leaves one, its errors are more useful than a summary.
+func closestBranch(results []result, path, keyword string) []Error {
+	var candidates []result
+	for _, r := range results {
+		wrongType := false
+		for _, err := range r.errs {
+			if err.Path == path && (err.Keyword == "type" || err.Keyword == "const" || err.Keyword == "false") {
+				wrongType = true
+				break
+			}
+		}
+		if !wrongType {
+			candidates = append(candidates, r)
+		}
+	}
+
+	if len(candidates) == 1 {
+		return candidates[0].errs
+	}
+	return []Error{{Path: path, Keyword: keyword, Message: fmt.Sprintf("does not match any schema in %s", keyword)}}
+}
+
+// unknownProperty describes a property the schema doesn't allow, with the
+// closest declared name when it looks like a typo
+func unknownProperty(name string, properties map[string]interface{}) string {
+	best, bestDistance := "", 0
+	for _, known := range sortedKeys(properties) {
+		d := distance(strings.ToLower(name), strings.ToLower(known))
+		if best == "" || d < bestDistance {
+			best, bestDistance = known, d
+		}
+	}
+
+	if best != "" && bestDistance <= max(1, len(best)/3) {
+		return fmt.Sprintf("is not allowed (did you mean %q?)", best)
+	}
+	return "is not allowed"
+}
+
+// distance is the edit distance between a and b, counting a swap of two
+// adjacent characters as one edit
+func distance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	d := make([][]int, len(ra)+1)
+	for i := range d {
+		d[i] = make([]int, len(rb)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			d[i][j] = min(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				d[i][j] = min(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+	return d[len(ra)][len(rb)]
+}
+
+func withEntry(m map[string]interface{}, key string, value interface{}) map[string]interface{} {
+	copied := make(map[string]interface{}, len(m)+1)
+	for k, v := range m {
+		copied[k] = v
+	}
+	copied[key] = value
+	return copied
+}
+
+var identifier = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// propertyPath appends a property to a JSON path, bracketed when it isn't
+// a plain identifier
+func propertyPath(path, name string) string {
+	if identifier.MatchString(name) {
+		return path + "." + name
+	}
+	return path + "[" + strconv.Quote(name) + "]"
+}
+
+func indexPath(path string, i int) string {
+	return path + "[" + strconv.Itoa(i) + "]"
+}
+
+// typeOf names the JSON type of a decoded value
+func typeOf(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	if _, ok := number(v); ok {
+		if isInteger(v) {
+			return "integer"
+		}
+		return "number"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+func hasType(v interface{}, name string) bool {
+	switch name {
+	case "integer":
+		return isInteger(v)
+	case "number":
+		_, ok := number(v)
+		return ok
+	}
+	t := typeOf(v)
+	return t == name || (t == "integer" && name == "number")
+}
+
+// number converts any of the numeric types a decoded value may hold
+func number(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// isInteger follows draft 2020-12: any number with a zero fractional part
+// is an integer, 1.0 included
+func isInteger(v interface{}) bool {
+	switch n := v.(type) {
+	case int, int64:
+		return true
+	case json.Number:
+		f, ok := new(big.Float).SetString(n.String())
+		return ok && f.IsInt()
+	}
+	f, ok := number(v)
+	return ok && !math.IsInf(f, 0) && f == math.Trunc(f)
+}
+
+// isMultiple checks multipleOf with exact decimal arithmetic, so 0.3 is a
+// multiple of 0.1
+func isMultiple(v, divisor interface{}) bool {
+	x, ok1 := decimal(v)
+	d, ok2 := decimal(divisor)
+	if !ok1 || !ok2 || d.Sign() == 0 {
+		return true
+	}
+	return new(big.Rat).Quo(x, d).IsInt()
+}
+
+func decimal(v interface{}) (*big.Rat, bool) {
+	s := ""
+	if n, ok := v.(json.Number); ok {
+		s = n.String()
+	} else if f, ok := number(v); ok {
+		s = strconv.FormatFloat(f, 'g', -1, 64)
+	} else {
+		return nil, false
+	}
+	return new(big.Rat).SetString(s)
+}
+
+// count reads a non-negative integer keyword value
+func count(v interface{}) (int, bool) {
+	f, ok := number(v)
+	if !ok || f < 0 || f != math.Trunc(f) {
+		return 0, false
+	}
+	return int(f), true
+}
+
+// equal compares decoded JSON values, treating numbers by value
+func equal(a, b interface{}) bool {
+	if x, ok := number(a); ok {
+		y, ok := number(b)
+		return ok && x == y
+	}
+
+	switch a := a.(type) {
+	case []interface{}:
+		b, ok := b.([]interface{})
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for i := range a {
+			if !equal(a[i], b[i]) {
+				return false
+			}
+		}
+		return true
+	case map[string]interface{}:
+		b, ok := b.(map[string]interface{})
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for k, v := range a {
+			w, ok := b[k]
+			if !ok || !equal(v, w) {
+				return false
+			}
+		}
+		return true
+	}
+	return a == b
+}
+
+func plural(n int, word string) string {
+	switch {
+	case n == 1:
+		return "1 " + word
+	case strings.HasSuffix(word, "y"):
+		return fmt.Sprintf("%d %sies", n, strings.TrimSuffix(word, "y"))
+	}
+	return fmt.Sprintf("%d %ss", n, word)
+}
+
+func formatNumber(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+func describeValue(v interface{}) string {
+	out, _ := json.Marshal(v)
+	return string(out)
+}
+
+// describeValues lists enum values, or just how many there are when the
+// list is long
+func describeValues(values []interface{}) string {
+	if len(values) > 10 {
+		return fmt.Sprintf("the %d allowed values", len(values))
+	}
+	described := make([]string, len(values))
+	for i, v := range values {
+		described[i] = describeValue(v)
+	}
+	return strings.Join(described, ", ")
+}
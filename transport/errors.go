@@ -55,7 +55,7 @@ func generateHints(operation string, err error) []string {
 	}
 
 	if strings.Contains(errMsg, "timeout") || strings.Contains(errMsg, "deadline exceeded") {
-		hints = append(hints, "The server might be slow to respond - try increasing timeout")
+		hints = append(hints, "The server might be slow to respond - try increasing --timeout")
 		hints = append(hints, "Check your network connection")
 		hints = append(hints, "Verify the server is not overloaded")
 	}
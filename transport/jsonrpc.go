@@ -0,0 +1,192 @@
+package transport
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// JSONRPCVersion is the only version of JSON-RPC that MCP speaks
+const JSONRPCVersion = "2.0"
+
+// Standard JSON-RPC 2.0 error codes, plus the ones MCP defines
+const (
+	CodeParseError       = -32700
+	CodeInvalidRequest   = -32600
+	CodeMethodNotFound   = -32601
+	CodeInvalidParams    = -32602
+	CodeInternalError    = -32603
+	CodeResourceNotFound = -32002
+)
+
+// ID is a JSON-RPC request ID, which may be either a string or a number.
+// IDs are comparable, so they can be used as map keys.
+type ID struct {
+	num   int64
+	str   string
+	isStr bool
+}
+
+// IntID returns a numeric request ID
+func IntID(n int64) ID {
+	return ID{num: n}
+}
+
+// StringID returns a string request ID
+func StringID(s string) ID {
+	return ID{str: s, isStr: true}
+}
+
+// IsString reports whether the ID was a JSON string
+func (id ID) IsString() bool {
+	return id.isStr
+}
+
+func (id ID) String() string {
+	if id.isStr {
+		return strconv.Quote(id.str)
+	}
+	return strconv.FormatInt(id.num, 10)
+}
+
+func (id ID) MarshalJSON() ([]byte, error) {
+	if id.isStr {
+		return json.Marshal(id.str)
+	}
+	return []byte(strconv.FormatInt(id.num, 10)), nil
+}
+
+func (id *ID) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+
+	if len(data) > 0 && data[0] == '"' {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		*id = StringID(s)
+		return nil
+	}
+
+	n, err := strconv.ParseInt(string(data), 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid JSON-RPC id %s: must be a string or an integer", data)
+	}
+	*id = IntID(n)
+	return nil
+}
+
+// RPCError is the error object of a JSON-RPC response
+type RPCError struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+func (e *RPCError) Error() string {
+	if len(e.Data) > 0 {
+		return fmt.Sprintf("%s (code %d, data: %s)", e.Message, e.Code, e.Data)
+	}
+	return fmt.Sprintf("%s (code %d)", e.Message, e.Code)
+}
+
+type RPCRequest struct {
+	JSONRPC string      `json:"jsonrpc"`
+	ID      ID          `json:"id"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params,omitempty"`
+}
+
+// RPCNotification is a message that expects no response
+type RPCNotification struct {
+	JSONRPC string      `json:"jsonrpc"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params,omitempty"`
+}
+
+type RPCResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      ID              `json:"id"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *RPCError       `json:"error,omitempty"`
+}
+
+// RPCMessage is any message a server can send: a response, a notification
+// or a request addressed to the client
+type RPCMessage struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      *ID             `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *RPCError       `json:"error,omitempty"`
+}
+
+// IsNotification reports whether the message is a notification (no ID)
+func (m RPCMessage) IsNotification() bool {
+	return m.Method != "" && m.ID == nil
+}
+
+// IsRequest reports whether the server expects the client to answer
+func (m RPCMessage) IsRequest() bool {
+	return m.Method != "" && m.ID != nil
+}
+
+// IsResponse reports whether the message answers one of our requests
+func (m RPCMessage) IsResponse() bool {
+	return m.Method == ""
+}
+
+// Response converts a response message into an RPCResponse
+func (m RPCMessage) Response() *RPCResponse {
+	resp := &RPCResponse{
+		JSONRPC: m.JSONRPC,
+		Result:  m.Result,
+		Error:   m.Error,
+	}
+	if m.ID != nil {
+		resp.ID = *m.ID
+	}
+	return resp
+}
+
+// RPCBatch is a JSON-RPC batch. It decodes from either an array or a single
+// message, so payloads can be parsed without knowing which one they are.
+type RPCBatch []RPCMessage
+
+func (b *RPCBatch) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+
+	if len(data) > 0 && data[0] == '[' {
+		var msgs []RPCMessage
+		if err := json.Unmarshal(data, &msgs); err != nil {
+			return err
+		}
+		if len(msgs) == 0 {
+			return fmt.Errorf("empty JSON-RPC batch")
+		}
+		*b = msgs
+		return nil
+	}
+
+	var msg RPCMessage
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return err
+	}
+	*b = RPCBatch{msg}
+	return nil
+}
+
+// DecodeMessages parses a payload holding a single message or a batch
+func DecodeMessages(data []byte) ([]RPCMessage, error) {
+	var batch RPCBatch
+	if err := json.Unmarshal(data, &batch); err != nil {
+		return nil, err
+	}
+	return batch, nil
+}
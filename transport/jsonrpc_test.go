@@ -0,0 +1,104 @@
+package transport
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestIDJSON(t *testing.T) {
+	tests := []struct {
+		json string
+		id   ID
+	}{
+		{`1`, IntID(1)},
+		{`0`, IntID(0)},
+		{`-7`, IntID(-7)},
+		{`9007199254740993`, IntID(9007199254740993)},
+		{`"1"`, StringID("1")},
+		{`""`, StringID("")},
+		{`"req-\"a\""`, StringID(`req-"a"`)},
+	}
+
+	for _, tt := range tests {
+		var id ID
+		if err := json.Unmarshal([]byte(tt.json), &id); err != nil {
+			t.Errorf("Unmarshal(%s): %v", tt.json, err)
+			continue
+		}
+		if id != tt.id {
+			t.Errorf("Unmarshal(%s) = %v, want %v", tt.json, id, tt.id)
+		}
+		if out, _ := json.Marshal(id); string(out) != tt.json {
+			t.Errorf("Marshal(%v) = %s, want %s", id, out, tt.json)
+		}
+	}
+
+	// The same digits as a string and a number are different IDs
+	if IntID(1) == StringID("1") {
+		t.Error("IntID(1) == StringID(\"1\")")
+	}
+}
+
+func TestIDRejectsNonIntegers(t *testing.T) {
+	for _, data := range []string{`1.5`, `1e3`, `true`, `{}`, `[1]`} {
+		var id ID
+		if err := json.Unmarshal([]byte(data), &id); err == nil {
+			t.Errorf("Unmarshal(%s) = %v, want an error", data, id)
+		}
+	}
+}
+
+func TestDecodeMessages(t *testing.T) {
+	tests := []struct {
+		data    string
+		want    []string
+		wantErr bool
+	}{
+		{data: `{"jsonrpc":"2.0","id":1,"result":{}}`, want: []string{"response"}},
+		{data: ` {"jsonrpc":"2.0","method":"notifications/progress","params":{}}`, want: []string{"notification"}},
+		{data: `{"jsonrpc":"2.0","id":"a","method":"ping"}`, want: []string{"request"}},
+		{
+			data: `[{"jsonrpc":"2.0","method":"notifications/message"},{"jsonrpc":"2.0","id":"x","method":"roots/list"},{"jsonrpc":"2.0","id":2,"error":{"code":-32601,"message":"nope"}}]`,
+			want: []string{"notification", "request", "response"},
+		},
+		{data: `[]`, wantErr: true},
+		{data: `{"jsonrpc":`, wantErr: true},
+		{data: `{"jsonrpc":"2.0","id":1.5,"result":{}}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		msgs, err := DecodeMessages([]byte(tt.data))
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("DecodeMessages(%s) = %v, want an error", tt.data, msgs)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("DecodeMessages(%s): %v", tt.data, err)
+			continue
+		}
+
+		var kinds []string
+		for _, msg := range msgs {
+			switch {
+			case msg.IsRequest():
+				kinds = append(kinds, "request")
+			case msg.IsNotification():
+				kinds = append(kinds, "notification")
+			case msg.IsResponse():
+				kinds = append(kinds, "response")
+			}
+		}
+		if len(kinds) != len(tt.want) {
+			t.Errorf("DecodeMessages(%s) kinds = %v, want %v", tt.data, kinds, tt.want)
+			continue
+		}
+		for i := range kinds {
+			if kinds[i] != tt.want[i] {
+				t.Errorf("DecodeMessages(%s) kinds = %v, want %v", tt.data, kinds, tt.want)
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package transport
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// router matches messages read from a stream to the Send call waiting for
+// them and hands notifications and server requests to the Listen handlers
+type router struct {
+	mu          sync.Mutex
+	pending     map[ID]chan *RPCResponse
+	handlers    map[int]func(RPCMessage)
+	nextHandler int
+	done        chan struct{}
+	err         error
+}
+
+func newRouter() *router {
+	return &router{
+		pending:  make(map[ID]chan *RPCResponse),
+		handlers: make(map[int]func(RPCMessage)),
+		done:     make(chan struct{}),
+	}
+}
+
+// expect registers interest in the response with the given ID. The returned
+// channel receives exactly one response.
+func (r *router) expect(id ID) (chan *RPCResponse, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.err != nil {
+		return nil, r.err
+	}
+	if _, exists := r.pending[id]; exists {
+		return nil, fmt.Errorf("request ID %v is already in flight", id)
+	}
+
+	ch := make(chan *RPCResponse, 1)
+	r.pending[id] = ch
+	return ch, nil
+}
+
+// forget drops a pending request, e.g. after the POST carrying it failed
+func (r *router) forget(id ID) {
+	r.mu.Lock()
+	delete(r.pending, id)
+	r.mu.Unlock()
+}
+
+// wait blocks until the response arrives, the stream goes away or ctx is done
+func (r *router) wait(ctx context.Context, id ID, ch chan *RPCResponse) (*RPCResponse, error) {
+	select {
+	case resp := <-ch:
+		return resp, nil
+	case <-r.done:
+		r.forget(id)
+		return nil, r.err
+	case <-ctx.Done():
+		r.forget(id)
+		return nil, ctx.Err()
+	}
+}
+
+// listen registers a handler until ctx is done or the stream goes away
+func (r *router) listen(ctx context.Context, handler func(RPCMessage)) error {
+	r.mu.Lock()
+	id := r.nextHandler
+	r.nextHandler++
+	r.handlers[id] = handler
+	r.mu.Unlock()
+
+	defer func() {
+		r.mu.Lock()
+		delete(r.handlers, id)
+		r.mu.Unlock()
+	}()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-r.done:
+		if r.err == errTransportClosed {
+			return nil
+		}
+		return r.err
+	}
+}
+
+// route delivers a payload read from the stream, which may be a batch
+func (r *router) route(data []byte) {
+	msgs, err := DecodeMessages(data)
+	if err != nil {
+		return
+	}
+
+	for _, msg := range msgs {
+		r.deliver(msg)
+	}
+}
+
+func (r *router) deliver(msg RPCMessage) {
+	if msg.IsResponse() && msg.ID != nil {
+		r.mu.Lock()
+		ch, ok := r.pending[*msg.ID]
+		delete(r.pending, *msg.ID)
+		r.mu.Unlock()
+
+		if ok {
+			ch <- msg.Response()
+			return
+		}
+	}
+
+	r.mu.Lock()
+	handlers := make([]func(RPCMessage), 0, len(r.handlers))
+	for _, h := range r.handlers {
+		handlers = append(handlers, h)
+	}
+	r.mu.Unlock()
+
+	for _, h := range handlers {
+		h(msg)
+	}
+}
+
+// fail marks the stream as gone and releases every waiting caller
+func (r *router) fail(err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.err != nil {
+		return
+	}
+	r.err = err
+	close(r.done)
+}
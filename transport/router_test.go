@@ -0,0 +1,174 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRouterCorrelatesIDs(t *testing.T) {
+	r := newRouter()
+
+	ids := []ID{IntID(1), StringID("1"), IntID(2), StringID("req-2")}
+	chans := make([]chan *RPCResponse, len(ids))
+	for i, id := range ids {
+		ch, err := r.expect(id)
+		if err != nil {
+			t.Fatalf("expect(%v): %v", id, err)
+		}
+		chans[i] = ch
+	}
+
+	// Answers come back out of order, one of them in a batch with a
+	// notification
+	r.route([]byte(`{"jsonrpc":"2.0","id":"1","result":{"n":"string one"}}`))
+	r.route([]byte(`[
+		{"jsonrpc":"2.0","method":"notifications/progress","params":{}},
+		{"jsonrpc":"2.0","id":"req-2","result":{"n":"string two"}},
+		{"jsonrpc":"2.0","id":1,"result":{"n":"number one"}}
+	]`))
+	r.route([]byte(`{"jsonrpc":"2.0","id":2,"error":{"code":-32601,"message":"Method not found"}}`))
+
+	want := []string{`{"n":"number one"}`, `{"n":"string one"}`, "", `{"n":"string two"}`}
+	for i, id := range ids {
+		resp, err := r.wait(context.Background(), id, chans[i])
+		if err != nil {
+			t.Fatalf("wait(%v): %v", id, err)
+		}
+		if resp.ID != id {
+			t.Errorf("response ID = %v, want %v", resp.ID, id)
+		}
+		if string(resp.Result) != want[i] {
+			t.Errorf("result for %v = %s, want %s", id, resp.Result, want[i])
+		}
+	}
+}
+
+func TestRouterHandsOtherMessagesToListeners(t *testing.T) {
+	r := newRouter()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var mu sync.Mutex
+	var got []string
+	done := make(chan error, 1)
+	go func() {
+		done <- r.listen(ctx, func(msg RPCMessage) {
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case msg.IsRequest():
+				got = append(got, "request "+msg.ID.String())
+			case msg.IsNotification():
+				got = append(got, "notification "+msg.Method)
+			default:
+				got = append(got, "response "+msg.ID.String())
+			}
+		})
+	}()
+	waitFor(t, func() bool {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		return len(r.handlers) == 1
+	})
+
+	r.route([]byte(`[
+		{"jsonrpc":"2.0","id":"s-1","method":"roots/list"},
+		{"jsonrpc":"2.0","method":"notifications/message","params":{}},
+		{"jsonrpc":"2.0","id":99,"result":{}}
+	]`))
+	r.route([]byte(`not json`))
+
+	mu.Lock()
+	want := []string{`request "s-1"`, "notification notifications/message", "response 99"}
+	if len(got) != len(want) {
+		t.Errorf("handled %v, want %v", got, want)
+	} else {
+		for i := range want {
+			if got[i] != want[i] {
+				t.Errorf("handled %v, want %v", got, want)
+				break
+			}
+		}
+	}
+	mu.Unlock()
+
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("listen() = %v, want context.Canceled", err)
+	}
+}
+
+func TestRouterRejectsDuplicateIDs(t *testing.T) {
+	r := newRouter()
+	if _, err := r.expect(IntID(7)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.expect(IntID(7)); err == nil {
+		t.Error("expect() of an ID in flight succeeded")
+	}
+	if _, err := r.expect(StringID("7")); err != nil {
+		t.Errorf("expect(\"7\") next to 7: %v", err)
+	}
+
+	r.forget(IntID(7))
+	if _, err := r.expect(IntID(7)); err != nil {
+		t.Errorf("expect() after forget: %v", err)
+	}
+}
+
+func TestRouterFailReleasesWaiters(t *testing.T) {
+	r := newRouter()
+	ch, err := r.expect(IntID(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	streamErr := errors.New("stream closed")
+	go r.fail(streamErr)
+
+	if _, err := r.wait(context.Background(), IntID(1), ch); err != streamErr {
+		t.Errorf("wait() = %v, want %v", err, streamErr)
+	}
+	if _, err := r.expect(IntID(2)); err != streamErr {
+		t.Errorf("expect() after fail = %v, want %v", err, streamErr)
+	}
+
+	// Closing the transport is not an error for listeners
+	closed := newRouter()
+	closed.fail(errTransportClosed)
+	if err := closed.listen(context.Background(), func(RPCMessage) {}); err != nil {
+		t.Errorf("listen() after close = %v, want nil", err)
+	}
+}
+
+func TestRouterWaitHonoursContext(t *testing.T) {
+	r := newRouter()
+	ch, err := r.expect(IntID(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := r.wait(ctx, IntID(1), ch); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("wait() = %v, want context.DeadlineExceeded", err)
+	}
+
+	// The late response has nobody waiting and must not block
+	r.route([]byte(`{"jsonrpc":"2.0","id":1,"result":{}}`))
+}
+
+// waitFor polls cond until it holds or a second has passed
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -1,19 +1,32 @@
 package transport
 
 import (
-	"bufio"
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
-	"strings"
+	"net/url"
 	"sync"
 )
 
+// errTransportClosed is reported to callers still waiting when Close is called
+var errTransportClosed = errors.New("transport closed")
+
+// sseTransport implements the 2024-11-05 HTTP+SSE transport. The client
+// keeps one GET stream open, learns the POST URL from the "endpoint" event
+// and receives every response asynchronously on that stream.
 type sseTransport struct {
 	url       string
 	client    *http.Client
+	router    *router
+	startOnce sync.Once
+	endpoint  string
+	ready     chan struct{}
+	stream    io.ReadCloser
+	mu        sync.Mutex
 	closeOnce sync.Once
 	closeCh   chan struct{}
 }
@@ -22,126 +35,226 @@ func NewSSE(url string) Transport {
 	return &sseTransport{
 		url:     url,
 		client:  &http.Client{},
+		router:  newRouter(),
+		ready:   make(chan struct{}),
 		closeCh: make(chan struct{}),
 	}
 }
 
-func (t *sseTransport) Send(req RPCRequest) (*RPCResponse, error) {
-	body, err := json.Marshal(req)
-	if err != nil {
-		return nil, err
+// connect opens the event stream on first use and waits for the endpoint
+// event. The stream outlives ctx; only the wait is bounded by it.
+func (t *sseTransport) connect(ctx context.Context) error {
+	t.startOnce.Do(func() {
+		go t.run()
+	})
+
+	select {
+	case <-t.ready:
+		return nil
+	case <-t.router.done:
+		return t.router.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// run owns the GET stream for the lifetime of the transport
+func (t *sseTransport) run() {
+	select {
+	case <-t.closeCh:
+		return
+	default:
 	}
 
-	httpReq, err := http.NewRequest("POST", t.url, bytes.NewReader(body))
+	req, err := http.NewRequest("GET", t.url, nil)
 	if err != nil {
-		return nil, err
+		t.router.fail(err)
+		return
 	}
 
-	httpReq.Header.Set("Content-Type", "application/json")
-	httpReq.Header.Set("Accept", "text/event-stream")
+	req.Header.Set("Accept", "text/event-stream")
+	req.Header.Set("Cache-Control", "no-cache")
 
-	resp, err := t.client.Do(httpReq)
+	resp, err := t.client.Do(req)
 	if err != nil {
-		return nil, NewConnectionError("sse", t.url, err)
+		t.router.fail(NewConnectionError("sse", t.url, err))
+		return
 	}
-	defer resp.Body.Close()
 
-	if resp.Header.Get("Content-Type") == "text/event-stream" {
-		return t.readSSEResponse(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		resp.Body.Close()
+		t.router.fail(fmt.Errorf("server returned HTTP %d: %s", resp.StatusCode, bytes.TrimSpace(data)))
+		return
+	}
+
+	t.mu.Lock()
+	t.stream = resp.Body
+	t.mu.Unlock()
+
+	// Close may have raced with the request
+	select {
+	case <-t.closeCh:
+		resp.Body.Close()
+	default:
+	}
+
+	t.readLoop(resp.Body)
+}
+
+func (t *sseTransport) readLoop(body io.ReadCloser) {
+	defer body.Close()
+
+	err := readSSE(body, func(ev sseEvent) error {
+		switch ev.Event {
+		case "endpoint":
+			return t.setEndpoint(ev.Data)
+		case "message":
+			t.router.route([]byte(ev.Data))
+		}
+		return nil
+	})
+
+	select {
+	case <-t.closeCh:
+		err = errTransportClosed
+	default:
+		if err == nil {
+			err = fmt.Errorf("SSE stream closed by server")
+		}
 	}
+	t.router.fail(err)
+}
 
-	// Fallback to regular JSON response
-	data, err := io.ReadAll(resp.Body)
+func (t *sseTransport) setEndpoint(data string) error {
+	base, err := url.Parse(t.url)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	var rpcResp RPCResponse
-	if err := json.Unmarshal(data, &rpcResp); err != nil {
-		return nil, fmt.Errorf("failed to parse JSON-RPC response: %v", err)
+	ref, err := url.Parse(data)
+	if err != nil {
+		return fmt.Errorf("invalid endpoint event %q: %v", data, err)
 	}
 
-	return &rpcResp, nil
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.endpoint == "" {
+		t.endpoint = base.ResolveReference(ref).String()
+		close(t.ready)
+	}
+	return nil
 }
 
-func (t *sseTransport) readSSEResponse(body io.Reader) (*RPCResponse, error) {
-	scanner := bufio.NewScanner(body)
-	var dataLines []string
-
-	for scanner.Scan() {
-		line := scanner.Text()
-
-		if strings.HasPrefix(line, "data: ") {
-			dataLines = append(dataLines, strings.TrimPrefix(line, "data: "))
-		} else if line == "" && len(dataLines) > 0 {
-			// End of event
-			data := strings.Join(dataLines, "\n")
-			var rpcResp RPCResponse
-			if err := json.Unmarshal([]byte(data), &rpcResp); err != nil {
-				return nil, fmt.Errorf("failed to parse SSE JSON-RPC response: %v", err)
-			}
-			return &rpcResp, nil
-		}
+func (t *sseTransport) Send(ctx context.Context, req RPCRequest) (*RPCResponse, error) {
+	if err := t.connect(ctx); err != nil {
+		return nil, err
 	}
 
-	if err := scanner.Err(); err != nil {
+	body, err := json.Marshal(req)
+	if err != nil {
 		return nil, err
 	}
 
-	return nil, fmt.Errorf("no valid SSE response received")
+	ch, err := t.router.expect(req.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := t.post(ctx, body); err != nil {
+		t.router.forget(req.ID)
+		if ctx.Err() != nil {
+			cancelRequest(t, req, err)
+		}
+		return nil, err
+	}
+
+	resp, err := t.router.wait(ctx, req.ID, ch)
+	if err != nil && ctx.Err() != nil {
+		cancelRequest(t, req, err)
+	}
+	return resp, err
 }
 
-func (t *sseTransport) Listen(handler func(RPCResponse)) error {
-	req, err := http.NewRequest("GET", t.url, nil)
+func (t *sseTransport) Notify(ctx context.Context, n RPCNotification) error {
+	if err := t.connect(ctx); err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(n)
 	if err != nil {
 		return err
 	}
+	return t.post(ctx, body)
+}
 
-	req.Header.Set("Accept", "text/event-stream")
-	req.Header.Set("Cache-Control", "no-cache")
-	req.Header.Set("Connection", "keep-alive")
+// Respond posts the answer to a server request to the endpoint
+func (t *sseTransport) Respond(ctx context.Context, r RPCResponse) error {
+	if err := t.connect(ctx); err != nil {
+		return err
+	}
 
-	resp, err := t.client.Do(req)
+	body, err := json.Marshal(r)
 	if err != nil {
-		return NewConnectionError("sse", t.url, err)
+		return err
 	}
-	defer resp.Body.Close()
+	return t.post(ctx, body)
+}
+
+// post delivers a message to the endpoint. The reply arrives on the stream,
+// so the POST body itself is ignored.
+func (t *sseTransport) post(ctx context.Context, body []byte) error {
+	t.mu.Lock()
+	endpoint := t.endpoint
+	t.mu.Unlock()
 
-	scanner := bufio.NewScanner(resp.Body)
-	var dataLines []string
-
-	for {
-		select {
-		case <-t.closeCh:
-			return nil
-		default:
-			if !scanner.Scan() {
-				if err := scanner.Err(); err != nil {
-					return err
-				}
-				return nil
-			}
-
-			line := scanner.Text()
-
-			if strings.HasPrefix(line, "data: ") {
-				dataLines = append(dataLines, strings.TrimPrefix(line, "data: "))
-			} else if line == "" && len(dataLines) > 0 {
-				// End of event
-				data := strings.Join(dataLines, "\n")
-				var rpcResp RPCResponse
-				if err := json.Unmarshal([]byte(data), &rpcResp); err == nil {
-					handler(rpcResp)
-				}
-				dataLines = nil
-			}
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := t.client.Do(httpReq)
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
 		}
+		return NewConnectionError("sse", endpoint, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("server returned HTTP %d: %s", resp.StatusCode, bytes.TrimSpace(data))
 	}
+
+	io.Copy(io.Discard, resp.Body)
+	return nil
+}
+
+// Listen delivers every message on the stream that is not a response to a
+// pending Send. It blocks until the stream ends, the transport is closed or
+// ctx is done.
+func (t *sseTransport) Listen(ctx context.Context, handler func(RPCMessage)) error {
+	if err := t.connect(ctx); err != nil {
+		return err
+	}
+
+	return t.router.listen(ctx, handler)
 }
 
 func (t *sseTransport) Close() error {
 	t.closeOnce.Do(func() {
 		close(t.closeCh)
+
+		t.mu.Lock()
+		if t.stream != nil {
+			t.stream.Close()
+		}
+		t.mu.Unlock()
+
+		t.router.fail(errTransportClosed)
 	})
 	return nil
 }
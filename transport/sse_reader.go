@@ -0,0 +1,84 @@
+package transport
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"strings"
+)
+
+// errStopReading can be returned from an SSE event handler to stop reading
+// the stream without reporting an error
+var errStopReading = errors.New("stop reading")
+
+// sseEvent is a single dispatched Server-Sent Event
+type sseEvent struct {
+	ID    string
+	Event string
+	Data  string
+}
+
+// readSSE parses a text/event-stream body and calls handle for every event.
+// Events without an explicit type are reported as "message".
+func readSSE(r io.Reader, handle func(sseEvent) error) error {
+	reader := bufio.NewReader(r)
+
+	var event sseEvent
+	var dataLines []string
+
+	dispatch := func() error {
+		defer func() {
+			event = sseEvent{}
+			dataLines = nil
+		}()
+
+		if len(dataLines) == 0 {
+			return nil
+		}
+		if event.Event == "" {
+			event.Event = "message"
+		}
+		event.Data = strings.Join(dataLines, "\n")
+		return handle(event)
+	}
+
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return err
+		}
+		atEOF := err == io.EOF
+
+		line = strings.TrimRight(line, "\r\n")
+
+		if line == "" {
+			if herr := dispatch(); herr != nil {
+				if herr == errStopReading {
+					return nil
+				}
+				return herr
+			}
+		} else if !strings.HasPrefix(line, ":") {
+			field, value, _ := strings.Cut(line, ":")
+			value = strings.TrimPrefix(value, " ")
+
+			switch field {
+			case "data":
+				dataLines = append(dataLines, value)
+			case "event":
+				event.Event = value
+			case "id":
+				event.ID = value
+			}
+		}
+
+		if atEOF {
+			// A stream that ends without a trailing blank line still
+			// carries a complete final event
+			if herr := dispatch(); herr != nil && herr != errStopReading {
+				return herr
+			}
+			return nil
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package transport
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadSSE(t *testing.T) {
+	tests := []struct {
+		name   string
+		stream string
+		want   []sseEvent
+	}{
+		{
+			name:   "single event",
+			stream: "data: {\"a\":1}\n\n",
+			want:   []sseEvent{{Event: "message", Data: `{"a":1}`}},
+		},
+		{
+			name:   "multi-line data",
+			stream: "data: first\ndata: second\ndata:third\n\n",
+			want:   []sseEvent{{Event: "message", Data: "first\nsecond\nthird"}},
+		},
+		{
+			name:   "event type and id",
+			stream: "id: 42\nevent: endpoint\ndata: /messages?session=abc\n\n",
+			want:   []sseEvent{{ID: "42", Event: "endpoint", Data: "/messages?session=abc"}},
+		},
+		{
+			name:   "comments and unknown fields",
+			stream: ": keep-alive\nretry: 1000\ndata: x\n: another\n\n:\n\n",
+			want:   []sseEvent{{Event: "message", Data: "x"}},
+		},
+		{
+			name:   "CRLF line endings",
+			stream: "event: message\r\ndata: a\r\ndata: b\r\n\r\ndata: c\r\n\r\n",
+			want: []sseEvent{
+				{Event: "message", Data: "a\nb"},
+				{Event: "message", Data: "c"},
+			},
+		},
+		{
+			name:   "fields reset between events",
+			stream: "event: custom\nid: 1\ndata: a\n\ndata: b\n\n",
+			want: []sseEvent{
+				{ID: "1", Event: "custom", Data: "a"},
+				{Event: "message", Data: "b"},
+			},
+		},
+		{
+			name:   "events without data are not dispatched",
+			stream: "event: ping\n\nid: 3\n\ndata: real\n\n",
+			want:   []sseEvent{{Event: "message", Data: "real"}},
+		},
+		{
+			name:   "only the first space is stripped",
+			stream: "data:  indented\ndata:\n\n",
+			want:   []sseEvent{{Event: "message", Data: " indented\n"}},
+		},
+		{
+			name:   "final event without a blank line",
+			stream: "data: one\n\ndata: two",
+			want: []sseEvent{
+				{Event: "message", Data: "one"},
+				{Event: "message", Data: "two"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		var got []sseEvent
+		err := readSSE(strings.NewReader(tt.stream), func(ev sseEvent) error {
+			got = append(got, ev)
+			return nil
+		})
+		if err != nil {
+			t.Errorf("%s: readSSE() = %v", tt.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: events = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestReadSSEStops(t *testing.T) {
+	stream := "data: 1\n\ndata: 2\n\ndata: 3\n\n"
+
+	var got []string
+	err := readSSE(strings.NewReader(stream), func(ev sseEvent) error {
+		got = append(got, ev.Data)
+		if ev.Data == "2" {
+			return errStopReading
+		}
+		return nil
+	})
+	if err != nil || !reflect.DeepEqual(got, []string{"1", "2"}) {
+		t.Errorf("readSSE() = %v with %v, want nil with [1 2]", err, got)
+	}
+
+	handlerErr := errors.New("bad event")
+	err = readSSE(strings.NewReader(stream), func(ev sseEvent) error {
+		return handlerErr
+	})
+	if err != handlerErr {
+		t.Errorf("readSSE() = %v, want %v", err, handlerErr)
+	}
+}
@@ -2,36 +2,58 @@ package transport
 
 import (
 	"bufio"
+	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"os/exec"
 	"sync"
+	"time"
 )
 
+// stopTimeout is how long Close waits for the server to exit before
+// signalling it, and again before killing it
+const stopTimeout = 2 * time.Second
+
+// stdioTransport talks newline-delimited JSON-RPC to a child process. A
+// single reader goroutine owns stdout and routes every line through the
+// router, so concurrent Send calls and Listen never compete for it.
 type stdioTransport struct {
-	cmd     *exec.Cmd
-	stdin   io.WriteCloser
-	stdout  io.ReadCloser
-	scanner *bufio.Scanner
-	mu      sync.Mutex
+	cmd       *exec.Cmd
+	stdin     io.WriteCloser
+	stdout    io.ReadCloser
+	router    *router
+	mu        sync.Mutex
+	writeMu   sync.Mutex
+	closeOnce sync.Once
 }
 
 func NewSTDIO(command string, args []string) Transport {
+	cmd := exec.Command(command, args...)
+	detachFromTerminal(cmd)
+
 	return &stdioTransport{
-		cmd: exec.Command(command, args...),
+		cmd:    cmd,
+		router: newRouter(),
 	}
 }
 
+// start launches the process on first use
 func (t *stdioTransport) start() error {
-	var err error
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.stdin != nil {
+		return nil
+	}
 
-	t.stdin, err = t.cmd.StdinPipe()
+	stdin, err := t.cmd.StdinPipe()
 	if err != nil {
 		return fmt.Errorf("failed to get stdin pipe: %v", err)
 	}
 
-	t.stdout, err = t.cmd.StdoutPipe()
+	stdout, err := t.cmd.StdoutPipe()
 	if err != nil {
 		return fmt.Errorf("failed to get stdout pipe: %v", err)
 	}
@@ -40,80 +62,143 @@ func (t *stdioTransport) start() error {
 		return WrapError("stdio start", err)
 	}
 
-	t.scanner = bufio.NewScanner(t.stdout)
+	t.stdin = stdin
+	t.stdout = stdout
+
+	go t.readLoop()
 	return nil
 }
 
-func (t *stdioTransport) Send(req RPCRequest) (*RPCResponse, error) {
-	t.mu.Lock()
-	defer t.mu.Unlock()
+func (t *stdioTransport) readLoop() {
+	reader := bufio.NewReader(t.stdout)
+
+	for {
+		line, err := reader.ReadBytes('\n')
+		if line = bytes.TrimSpace(line); len(line) > 0 {
+			t.router.route(line)
+		}
 
-	// Start the process if not already started
-	if t.stdin == nil {
-		if err := t.start(); err != nil {
-			return nil, err
+		if err != nil {
+			if err == io.EOF {
+				err = fmt.Errorf("server process closed stdout")
+			} else {
+				err = fmt.Errorf("failed to read from server: %v", err)
+			}
+			t.router.fail(err)
+			return
 		}
 	}
+}
+
+func (t *stdioTransport) Send(ctx context.Context, req RPCRequest) (*RPCResponse, error) {
+	if err := t.start(); err != nil {
+		return nil, err
+	}
 
-	// Send the request
 	data, err := json.Marshal(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal request: %v", err)
 	}
 
-	if _, err := t.stdin.Write(append(data, '\n')); err != nil {
-		return nil, fmt.Errorf("failed to write request: %v", err)
+	ch, err := t.router.expect(req.ID)
+	if err != nil {
+		return nil, err
 	}
 
-	// Read the response
-	if !t.scanner.Scan() {
-		if err := t.scanner.Err(); err != nil {
-			return nil, fmt.Errorf("failed to read response: %v", err)
-		}
-		return nil, fmt.Errorf("no response received")
+	if err := t.write(data); err != nil {
+		t.router.forget(req.ID)
+		return nil, err
 	}
 
-	var rpcResp RPCResponse
-	if err := json.Unmarshal(t.scanner.Bytes(), &rpcResp); err != nil {
-		return nil, fmt.Errorf("failed to parse response: %v", err)
+	resp, err := t.router.wait(ctx, req.ID, ch)
+	if err != nil && ctx.Err() != nil {
+		cancelRequest(t, req, err)
 	}
-
-	return &rpcResp, nil
+	return resp, err
 }
 
-func (t *stdioTransport) Listen(handler func(RPCResponse)) error {
-	t.mu.Lock()
-	if t.stdin == nil {
-		if err := t.start(); err != nil {
-			t.mu.Unlock()
-			return err
-		}
+func (t *stdioTransport) Notify(ctx context.Context, n RPCNotification) error {
+	data, err := json.Marshal(n)
+	if err != nil {
+		return fmt.Errorf("failed to marshal notification: %v", err)
 	}
-	t.mu.Unlock()
+	return t.write(data)
+}
 
-	for t.scanner.Scan() {
-		var rpcResp RPCResponse
-		if err := json.Unmarshal(t.scanner.Bytes(), &rpcResp); err == nil {
-			handler(rpcResp)
-		}
+// Respond writes the answer to a server request
+func (t *stdioTransport) Respond(ctx context.Context, r RPCResponse) error {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return fmt.Errorf("failed to marshal response: %v", err)
 	}
+	return t.write(data)
+}
 
-	if err := t.scanner.Err(); err != nil {
-		return fmt.Errorf("scanner error: %v", err)
+// write sends one message, starting the process first if nothing else has
+func (t *stdioTransport) write(data []byte) error {
+	if err := t.start(); err != nil {
+		return err
 	}
 
+	t.writeMu.Lock()
+	defer t.writeMu.Unlock()
+
+	if _, err := t.stdin.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write request: %v", err)
+	}
 	return nil
 }
 
-func (t *stdioTransport) Close() error {
-	if t.stdin != nil {
-		t.stdin.Close()
-	}
-	if t.stdout != nil {
-		t.stdout.Close()
-	}
-	if t.cmd != nil && t.cmd.Process != nil {
-		return t.cmd.Process.Kill()
+// Listen delivers notifications and server requests until the process exits,
+// the transport is closed or ctx is done
+func (t *stdioTransport) Listen(ctx context.Context, handler func(RPCMessage)) error {
+	if err := t.start(); err != nil {
+		return err
 	}
+
+	return t.router.listen(ctx, handler)
+}
+
+// Close stops the server the way the spec asks: it closes stdin, waits for
+// the process to exit and signals it after a grace period. Wrappers like
+// npx or uvx leave the real server running as their child, so the signals
+// go to the whole process group.
+func (t *stdioTransport) Close() error {
+	t.closeOnce.Do(func() {
+		t.router.fail(errTransportClosed)
+
+		t.mu.Lock()
+		defer t.mu.Unlock()
+
+		if t.stdin != nil {
+			t.stdin.Close()
+		}
+		if t.stdout != nil {
+			t.stdout.Close()
+		}
+		if t.cmd == nil || t.cmd.Process == nil {
+			return
+		}
+
+		exited := make(chan struct{})
+		go func() {
+			t.cmd.Wait()
+			close(exited)
+		}()
+
+		select {
+		case <-exited:
+		case <-time.After(stopTimeout):
+		}
+		stopProcessGroup(t.cmd.Process, false)
+
+		select {
+		case <-exited:
+		case <-time.After(stopTimeout):
+			stopProcessGroup(t.cmd.Process, true)
+			<-exited
+		}
+	})
+
 	return nil
 }
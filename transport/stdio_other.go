@@ -0,0 +1,18 @@
+//go:build !unix
+
+package transport
+
+import (
+	"os"
+	"os/exec"
+)
+
+func detachFromTerminal(cmd *exec.Cmd) {}
+
+// stopProcessGroup kills p when forced. There is no group to signal and
+// no gentler signal to send.
+func stopProcessGroup(p *os.Process, force bool) {
+	if force {
+		p.Kill()
+	}
+}
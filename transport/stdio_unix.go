@@ -0,0 +1,25 @@
+//go:build unix
+
+package transport
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// detachFromTerminal starts the server in its own process group, so Ctrl-C
+// in the terminal cancels the request instead of killing the server
+func detachFromTerminal(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+}
+
+// stopProcessGroup sends SIGTERM, or SIGKILL when forced, to the process
+// group p leads
+func stopProcessGroup(p *os.Process, force bool) {
+	sig := syscall.SIGTERM
+	if force {
+		sig = syscall.SIGKILL
+	}
+	syscall.Kill(-p.Pid, sig)
+}
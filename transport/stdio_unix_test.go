@@ -0,0 +1,64 @@
+//go:build unix
+
+package transport
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestStdioCloseStopsProcessGroup(t *testing.T) {
+	// Like npx, the shell starts the real work as a child and outlives
+	// neither its stdin nor its child; the child would be left running
+	script := `sleep 300 & echo "{\"jsonrpc\":\"2.0\",\"method\":\"started\",\"params\":{\"pid\":$!}}"; cat >/dev/null`
+	tr := NewSTDIO("sh", []string{"-c", script})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	pids := make(chan int, 1)
+	go tr.Listen(ctx, func(msg RPCMessage) {
+		var params struct{ PID int }
+		if msg.Method == "started" && json.Unmarshal(msg.Params, &params) == nil {
+			pids <- params.PID
+		}
+	})
+
+	var pid int
+	select {
+	case pid = <-pids:
+	case <-ctx.Done():
+		t.Fatal("server did not start")
+	}
+
+	start := time.Now()
+	if err := tr.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > stopTimeout {
+		t.Errorf("Close took %v, want the server to stop once stdin closed", elapsed)
+	}
+
+	waitFor(t, func() bool { return !running(pid) })
+}
+
+// running reports whether pid is a live process, not counting zombies
+// nobody reaped yet
+func running(pid int) bool {
+	if syscall.Kill(pid, 0) != nil {
+		return false
+	}
+	stat, err := os.ReadFile("/proc/" + strconv.Itoa(pid) + "/stat")
+	if err != nil {
+		return true
+	}
+	// The state follows the command name in parentheses
+	fields := strings.Fields(string(stat[strings.LastIndexByte(string(stat), ')')+1:]))
+	return len(fields) == 0 || fields[0] != "Z"
+}
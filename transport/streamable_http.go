@@ -2,51 +2,310 @@ package transport
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
+	"mime"
 	"net/http"
+	"sync"
+)
+
+const (
+	sessionIDHeader       = "Mcp-Session-Id"
+	protocolVersionHeader = "MCP-Protocol-Version"
 )
 
 type streamableHttpTransport struct {
-	url string
+	url       string
+	client    *http.Client
+	mu        sync.Mutex
+	sessionID string
+	version   string
+	router    *router
+	readyOnce sync.Once
+	ready     chan struct{}
+	closeOnce sync.Once
 }
 
 func NewStreamableHttp(url string) Transport {
-	return &streamableHttpTransport{url: url}
+	return &streamableHttpTransport{
+		url:    url,
+		client: &http.Client{},
+		router: newRouter(),
+		ready:  make(chan struct{}),
+	}
+}
+
+func (t *streamableHttpTransport) Send(ctx context.Context, req RPCRequest) (*RPCResponse, error) {
+	resp, err := t.send(ctx, req)
+	if err != nil && ctx.Err() != nil {
+		cancelRequest(t, req, err)
+		return nil, ctx.Err()
+	}
+	return resp, err
 }
 
-func (t *streamableHttpTransport) Send(req RPCRequest) (*RPCResponse, error) {
-	body, err := json.Marshal(req)
+func (t *streamableHttpTransport) send(ctx context.Context, req RPCRequest) (*RPCResponse, error) {
+	resp, err := t.post(ctx, req)
 	if err != nil {
 		return nil, err
 	}
+	defer resp.Body.Close()
+
+	// The server assigns the session when it answers initialize; every
+	// later request has to carry it
+	if req.Method == "initialize" {
+		if id := resp.Header.Get(sessionIDHeader); id != "" {
+			t.mu.Lock()
+			t.sessionID = id
+			t.mu.Unlock()
+		}
+	}
+
+	if err := t.checkStatus(resp); err != nil {
+		return nil, err
+	}
 
-	resp, err := http.Post(t.url, "application/json", bytes.NewReader(body))
+	if req.Method == "initialize" {
+		t.readyOnce.Do(func() { close(t.ready) })
+	}
+
+	if resp.StatusCode == http.StatusAccepted {
+		return nil, fmt.Errorf("server accepted %s request but sent no response", req.Method)
+	}
+
+	ch, err := t.router.expect(req.ID)
 	if err != nil {
-		return nil, NewConnectionError("streamable-http", t.url, err)
+		return nil, err
+	}
+	defer t.router.forget(req.ID)
+
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if mediaType == "text/event-stream" {
+		return t.readEventStreamResponse(resp.Body, ch)
 	}
-	defer resp.Body.Close()
 
 	data, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return nil, err
 	}
 
-	var rpcResp RPCResponse
-	if err := json.Unmarshal(data, &rpcResp); err != nil {
+	// The body may be a batch; anything besides our response goes to the
+	// Listen handlers
+	if _, err := DecodeMessages(data); err != nil {
 		return nil, fmt.Errorf("failed to parse JSON-RPC response: %v\nRaw: %s", err, string(data))
 	}
+	t.router.route(data)
+
+	select {
+	case rpcResp := <-ch:
+		return rpcResp, nil
+	default:
+		return nil, fmt.Errorf("server response did not contain a reply to request %v", req.ID)
+	}
+}
+
+// Notify POSTs a message that has no response; the server answers 202
+func (t *streamableHttpTransport) Notify(ctx context.Context, n RPCNotification) error {
+	resp, err := t.post(ctx, n)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return t.checkStatus(resp)
+}
+
+// Respond POSTs the answer to a server request; the server answers 202
+func (t *streamableHttpTransport) Respond(ctx context.Context, r RPCResponse) error {
+	resp, err := t.post(ctx, r)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
 
-	return &rpcResp, nil
+	return t.checkStatus(resp)
 }
 
-func (t *streamableHttpTransport) Listen(handler func(RPCResponse)) error {
-	// StreamableHttp is request/response — no continuous listen
-	return fmt.Errorf("Listen is not supported for StreamableHttp transport")
+func (t *streamableHttpTransport) post(ctx context.Context, msg interface{}) (*http.Response, error) {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", t.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Accept", "application/json, text/event-stream")
+	t.setSessionHeaders(httpReq)
+
+	resp, err := t.client.Do(httpReq)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, NewConnectionError("streamable-http", t.url, err)
+	}
+
+	return resp, nil
 }
 
+// readEventStreamResponse reads an SSE-upgraded POST response until the
+// response expected on ch arrives. Anything the server sends before it
+// (progress, logs, server requests) is handed to the Listen handlers.
+func (t *streamableHttpTransport) readEventStreamResponse(body io.Reader, ch chan *RPCResponse) (*RPCResponse, error) {
+	var rpcResp *RPCResponse
+
+	err := readSSE(body, func(ev sseEvent) error {
+		if ev.Event != "message" {
+			return nil
+		}
+
+		t.router.route([]byte(ev.Data))
+
+		select {
+		case rpcResp = <-ch:
+			return errStopReading
+		default:
+			return nil
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if rpcResp == nil {
+		return nil, fmt.Errorf("SSE stream ended before a response was received")
+	}
+
+	return rpcResp, nil
+}
+
+// Listen delivers notifications and server requests until ctx is done or
+// the transport is closed. Messages arriving on POST streams are always
+// delivered; the optional GET stream is opened on a best-effort basis since
+// servers are not required to offer it.
+func (t *streamableHttpTransport) Listen(ctx context.Context, handler func(RPCMessage)) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	go t.openStream(ctx)
+
+	return t.router.listen(ctx, handler)
+}
+
+// openStream reads the GET stream for server-initiated messages. The stream
+// belongs to a session, so it is only opened once initialize went through.
+func (t *streamableHttpTransport) openStream(ctx context.Context) {
+	select {
+	case <-t.ready:
+	case <-ctx.Done():
+		return
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", t.url, nil)
+	if err != nil {
+		return
+	}
+
+	req.Header.Set("Accept", "text/event-stream")
+	t.setSessionHeaders(req)
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return
+	}
+
+	readSSE(resp.Body, func(ev sseEvent) error {
+		if ev.Event == "message" {
+			t.router.route([]byte(ev.Data))
+		}
+		return nil
+	})
+}
+
+// Close ends the session on the server with a DELETE request. Servers that
+// do not allow clients to terminate sessions answer 405, which is fine.
 func (t *streamableHttpTransport) Close() error {
-	// Nothing to close for simple HTTP transport
+	t.closeOnce.Do(func() {
+		t.router.fail(errTransportClosed)
+
+		t.mu.Lock()
+		sessionID := t.sessionID
+		t.sessionID = ""
+		t.mu.Unlock()
+
+		if sessionID == "" {
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), notifyTimeout)
+		defer cancel()
+
+		req, err := http.NewRequestWithContext(ctx, "DELETE", t.url, nil)
+		if err != nil {
+			return
+		}
+		t.setSessionHeaders(req)
+		req.Header.Set(sessionIDHeader, sessionID)
+
+		if resp, err := t.client.Do(req); err == nil {
+			resp.Body.Close()
+		}
+	})
 	return nil
 }
+
+// SetProtocolVersion makes every later request carry MCP-Protocol-Version
+func (t *streamableHttpTransport) SetProtocolVersion(version string) {
+	t.mu.Lock()
+	t.version = version
+	t.mu.Unlock()
+}
+
+func (t *streamableHttpTransport) setSessionHeaders(req *http.Request) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.sessionID != "" {
+		req.Header.Set(sessionIDHeader, t.sessionID)
+	}
+	if t.version != "" {
+		req.Header.Set(protocolVersionHeader, t.version)
+	}
+}
+
+// checkStatus turns non-2xx responses into errors. A 404 on a request that
+// carried a session ID means the server dropped the session.
+func (t *streamableHttpTransport) checkStatus(resp *http.Response) error {
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+
+	if resp.StatusCode == http.StatusNotFound && resp.Request.Header.Get(sessionIDHeader) != "" {
+		t.mu.Lock()
+		t.sessionID = ""
+		t.mu.Unlock()
+		return &MCPError{
+			Operation: "streamable-http request",
+			Err:       fmt.Errorf("session expired (HTTP 404)"),
+			Hints: []string{
+				"The server no longer recognizes the session ID",
+				"Reconnect so a new session is initialized",
+			},
+		}
+	}
+
+	return fmt.Errorf("server returned HTTP %d: %s", resp.StatusCode, bytes.TrimSpace(data))
+}
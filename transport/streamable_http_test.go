@@ -0,0 +1,211 @@
+package transport
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeServer answers streamable HTTP requests and records what it got
+type fakeServer struct {
+	mu       sync.Mutex
+	requests []recordedRequest
+
+	// expired makes every request with a session ID fail with 404
+	expired bool
+}
+
+type recordedRequest struct {
+	method    string
+	session   string
+	version   string
+	rpcMethod string
+}
+
+func (f *fakeServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var msg RPCMessage
+	if r.Method == "POST" {
+		body, _ := io.ReadAll(r.Body)
+		json.Unmarshal(body, &msg)
+	}
+
+	f.mu.Lock()
+	f.requests = append(f.requests, recordedRequest{
+		method:    r.Method,
+		session:   r.Header.Get(sessionIDHeader),
+		version:   r.Header.Get(protocolVersionHeader),
+		rpcMethod: msg.Method,
+	})
+	expired := f.expired
+	f.mu.Unlock()
+
+	if expired && r.Header.Get(sessionIDHeader) != "" {
+		http.Error(w, "unknown session", http.StatusNotFound)
+		return
+	}
+
+	switch {
+	case r.Method == "GET":
+		http.Error(w, "no stream", http.StatusMethodNotAllowed)
+
+	case r.Method == "DELETE":
+		w.WriteHeader(http.StatusOK)
+
+	case msg.Method == "initialize":
+		w.Header().Set(sessionIDHeader, "session-1")
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"result":{"protocolVersion":"2025-06-18"}}`, msg.ID)
+
+	case msg.Method == "tools/call":
+		// Progress first, then the answer, over SSE
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprintf(w, "event: message\ndata: {\"jsonrpc\":\"2.0\",\"method\":\"notifications/progress\",\"params\":{\"progress\":1}}\n\n")
+		fmt.Fprintf(w, "data: {\"jsonrpc\":\"2.0\",\"id\":%s,\"result\":{\"content\":[]}}\n\n", msg.ID)
+
+	case msg.ID == nil:
+		w.WriteHeader(http.StatusAccepted)
+
+	default:
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"result":{}}`, msg.ID)
+	}
+}
+
+func (f *fakeServer) recorded() []recordedRequest {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]recordedRequest(nil), f.requests...)
+}
+
+func TestStreamableHttpSession(t *testing.T) {
+	fake := &fakeServer{}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	tr := NewStreamableHttp(server.URL)
+	ctx := context.Background()
+
+	resp, err := tr.Send(ctx, RPCRequest{JSONRPC: JSONRPCVersion, ID: IntID(1), Method: "initialize"})
+	if err != nil {
+		t.Fatalf("initialize: %v", err)
+	}
+	if resp.ID != IntID(1) {
+		t.Errorf("initialize response ID = %v, want 1", resp.ID)
+	}
+	tr.(ProtocolVersionSetter).SetProtocolVersion("2025-06-18")
+
+	if err := tr.Notify(ctx, RPCNotification{JSONRPC: JSONRPCVersion, Method: "notifications/initialized"}); err != nil {
+		t.Fatalf("notify: %v", err)
+	}
+	if _, err := tr.Send(ctx, RPCRequest{JSONRPC: JSONRPCVersion, ID: StringID("two"), Method: "tools/list"}); err != nil {
+		t.Fatalf("tools/list: %v", err)
+	}
+
+	if err := tr.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	want := []recordedRequest{
+		{method: "POST", rpcMethod: "initialize"},
+		{method: "POST", session: "session-1", version: "2025-06-18", rpcMethod: "notifications/initialized"},
+		{method: "POST", session: "session-1", version: "2025-06-18", rpcMethod: "tools/list"},
+		{method: "DELETE", session: "session-1", version: "2025-06-18"},
+	}
+	got := fake.recorded()
+	if len(got) != len(want) {
+		t.Fatalf("requests = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("request %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestStreamableHttpEventStreamResponse(t *testing.T) {
+	server := httptest.NewServer(&fakeServer{})
+	defer server.Close()
+
+	tr := NewStreamableHttp(server.URL)
+	defer tr.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	notifications := make(chan string, 1)
+	go tr.Listen(ctx, func(msg RPCMessage) {
+		if msg.IsNotification() {
+			notifications <- msg.Method
+		}
+	})
+	r := tr.(*streamableHttpTransport).router
+	waitFor(t, func() bool {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		return len(r.handlers) == 1
+	})
+
+	resp, err := tr.Send(ctx, RPCRequest{JSONRPC: JSONRPCVersion, ID: IntID(5), Method: "tools/call"})
+	if err != nil {
+		t.Fatalf("tools/call: %v", err)
+	}
+	if resp.ID != IntID(5) || string(resp.Result) != `{"content":[]}` {
+		t.Errorf("response = %+v", resp)
+	}
+	if got := <-notifications; got != "notifications/progress" {
+		t.Errorf("notification = %q, want notifications/progress", got)
+	}
+}
+
+func TestStreamableHttpSessionExpired(t *testing.T) {
+	fake := &fakeServer{}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	tr := NewStreamableHttp(server.URL)
+	defer tr.Close()
+
+	ctx := context.Background()
+	if _, err := tr.Send(ctx, RPCRequest{JSONRPC: JSONRPCVersion, ID: IntID(1), Method: "initialize"}); err != nil {
+		t.Fatalf("initialize: %v", err)
+	}
+
+	fake.mu.Lock()
+	fake.expired = true
+	fake.mu.Unlock()
+
+	_, err := tr.Send(ctx, RPCRequest{JSONRPC: JSONRPCVersion, ID: IntID(2), Method: "tools/list"})
+	var mcpErr *MCPError
+	if !errors.As(err, &mcpErr) || !strings.Contains(err.Error(), "session expired") {
+		t.Fatalf("tools/list after expiry = %v, want a session expired error", err)
+	}
+
+	// The session is dropped, so the next request goes without it
+	if _, err := tr.Send(ctx, RPCRequest{JSONRPC: JSONRPCVersion, ID: IntID(3), Method: "ping"}); err != nil {
+		t.Fatalf("ping after expiry: %v", err)
+	}
+	got := fake.recorded()
+	if last := got[len(got)-1]; last.session != "" {
+		t.Errorf("request after expiry carried session %q", last.session)
+	}
+}
+
+func TestStreamableHttpCloseWithoutSession(t *testing.T) {
+	fake := &fakeServer{}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	if err := NewStreamableHttp(server.URL).Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if got := fake.recorded(); len(got) != 0 {
+		t.Errorf("Close without a session sent %+v", got)
+	}
+}
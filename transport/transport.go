@@ -1,21 +1,49 @@
 package transport
 
-type RPCRequest struct {
-	JSONRPC string      `json:"jsonrpc"`
-	ID      int         `json:"id"`
-	Method  string      `json:"method"`
-	Params  interface{} `json:"params,omitempty"`
+import (
+	"context"
+	"time"
+)
+
+// Transport carries JSON-RPC messages to a server. Send blocks until the
+// response arrives or ctx is done; Listen blocks until ctx is done or the
+// transport is closed. Respond answers a request the server sent to the
+// client through Listen.
+type Transport interface {
+	Send(ctx context.Context, req RPCRequest) (*RPCResponse, error)
+	Notify(ctx context.Context, n RPCNotification) error
+	Respond(ctx context.Context, resp RPCResponse) error
+	Listen(ctx context.Context, handler func(RPCMessage)) error
+	Close() error
 }
 
-type RPCResponse struct {
-	JSONRPC string      `json:"jsonrpc"`
-	ID      int         `json:"id"`
-	Result  interface{} `json:"result,omitempty"`
-	Error   interface{} `json:"error,omitempty"`
+// ProtocolVersionSetter is implemented by transports whose wire format
+// depends on the negotiated protocol version
+type ProtocolVersionSetter interface {
+	SetProtocolVersion(version string)
 }
 
-type Transport interface {
-	Send(req RPCRequest) (*RPCResponse, error)
-	Listen(handler func(RPCResponse)) error
-	Close() error
+// notifyTimeout bounds fire-and-forget messages that are sent after the
+// caller's context is already gone
+const notifyTimeout = 5 * time.Second
+
+// cancelRequest tells the server to stop working on a request the caller
+// gave up on. The request is abandoned either way, so errors are ignored.
+// Per the spec, initialize is never cancelled.
+func cancelRequest(t Transport, req RPCRequest, cause error) {
+	if req.Method == "initialize" {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), notifyTimeout)
+	defer cancel()
+
+	t.Notify(ctx, RPCNotification{
+		JSONRPC: JSONRPCVersion,
+		Method:  "notifications/cancelled",
+		Params: map[string]interface{}{
+			"requestId": req.ID,
+			"reason":    cause.Error(),
+		},
+	})
 }
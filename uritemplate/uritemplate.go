@@ -0,0 +1,387 @@
+// Package uritemplate implements RFC 6570 URI templates up to Level 4,
+// which MCP servers use to describe parameterized resources.
+package uritemplate
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Template is a parsed URI template
+type Template struct {
+	raw   string
+	parts []part
+}
+
+// part is either a literal or an expression, never both
+type part struct {
+	literal string
+	expr    *expression
+}
+
+type expression struct {
+	op   operator
+	vars []varspec
+}
+
+type varspec struct {
+	name    string
+	prefix  int // 0 means no prefix modifier
+	explode bool
+}
+
+// operator describes how an expression is expanded, per RFC 6570
+// appendix A
+type operator struct {
+	first    string
+	sep      string
+	named    bool
+	ifEmpty  string
+	reserved bool // allow reserved characters through unencoded
+}
+
+var operators = map[byte]operator{
+	'+': {first: "", sep: ",", reserved: true},
+	'#': {first: "#", sep: ",", reserved: true},
+	'.': {first: ".", sep: "."},
+	'/': {first: "/", sep: "/"},
+	';': {first: ";", sep: ";", named: true},
+	'?': {first: "?", sep: "&", named: true, ifEmpty: "="},
+	'&': {first: "&", sep: "&", named: true, ifEmpty: "="},
+}
+
+var simpleOperator = operator{first: "", sep: ","}
+
+// Operators reserved by the RFC for future extensions
+const reservedOperators = "=,!@|"
+
+// maxPrefix is the largest prefix modifier the RFC allows
+const maxPrefix = 9999
+
+// Parse parses a URI template
+func Parse(raw string) (*Template, error) {
+	t := &Template{raw: raw}
+
+	rest := raw
+	for rest != "" {
+		open := strings.IndexByte(rest, '{')
+		if open < 0 {
+			if strings.IndexByte(rest, '}') >= 0 {
+				return nil, fmt.Errorf("invalid URI template %q: unmatched '}'", raw)
+			}
+			t.parts = append(t.parts, part{literal: rest})
+			break
+		}
+
+		if open > 0 {
+			if strings.IndexByte(rest[:open], '}') >= 0 {
+				return nil, fmt.Errorf("invalid URI template %q: unmatched '}'", raw)
+			}
+			t.parts = append(t.parts, part{literal: rest[:open]})
+		}
+
+		end := strings.IndexByte(rest[open:], '}')
+		if end < 0 {
+			return nil, fmt.Errorf("invalid URI template %q: unclosed '{'", raw)
+		}
+
+		expr, err := parseExpression(rest[open+1 : open+end])
+		if err != nil {
+			return nil, fmt.Errorf("invalid URI template %q: %v", raw, err)
+		}
+		t.parts = append(t.parts, part{expr: expr})
+
+		rest = rest[open+end+1:]
+	}
+
+	return t, nil
+}
+
+func parseExpression(s string) (*expression, error) {
+	if s == "" {
+		return nil, fmt.Errorf("empty expression")
+	}
+
+	expr := &expression{op: simpleOperator}
+	if op, ok := operators[s[0]]; ok {
+		expr.op = op
+		s = s[1:]
+	} else if strings.IndexByte(reservedOperators, s[0]) >= 0 {
+		return nil, fmt.Errorf("operator %q is reserved", s[0])
+	}
+
+	for _, spec := range strings.Split(s, ",") {
+		v, err := parseVarspec(spec)
+		if err != nil {
+			return nil, err
+		}
+		expr.vars = append(expr.vars, v)
+	}
+	return expr, nil
+}
+
+func parseVarspec(s string) (varspec, error) {
+	var v varspec
+
+	if strings.HasSuffix(s, "*") {
+		v.explode = true
+		s = s[:len(s)-1]
+	} else if i := strings.IndexByte(s, ':'); i >= 0 {
+		n, err := strconv.Atoi(s[i+1:])
+		if err != nil || n < 1 || n > maxPrefix {
+			return v, fmt.Errorf("invalid prefix modifier in %q", s)
+		}
+		v.prefix = n
+		s = s[:i]
+	}
+
+	if !validVarname(s) {
+		return v, fmt.Errorf("invalid variable name %q", s)
+	}
+	v.name = s
+	return v, nil
+}
+
+// validVarname checks varname = varchar *( ["."] varchar ), where varchar
+// is ALPHA / DIGIT / "_" / pct-encoded
+func validVarname(s string) bool {
+	if s == "" || s[0] == '.' || s[len(s)-1] == '.' {
+		return false
+	}
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case isAlpha(c) || isDigit(c) || c == '_':
+		case c == '.':
+			if s[i-1] == '.' {
+				return false
+			}
+		case c == '%':
+			if i+2 >= len(s) || !isHex(s[i+1]) || !isHex(s[i+2]) {
+				return false
+			}
+			i += 2
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// String returns the template as it was parsed
+func (t *Template) String() string {
+	return t.raw
+}
+
+// Varnames returns the names of the template's variables in the order they
+// first appear
+func (t *Template) Varnames() []string {
+	var names []string
+	seen := map[string]bool{}
+
+	for _, p := range t.parts {
+		if p.expr == nil {
+			continue
+		}
+		for _, v := range p.expr.vars {
+			if !seen[v.name] {
+				seen[v.name] = true
+				names = append(names, v.name)
+			}
+		}
+	}
+	return names
+}
+
+// Expand substitutes vars into the template. A value may be a string, a
+// []string (a list) or a map[string]string (an associative array, expanded
+// in key order). Missing variables, empty lists and empty maps are
+// undefined and expand to nothing.
+func (t *Template) Expand(vars map[string]interface{}) (string, error) {
+	var sb strings.Builder
+
+	for _, p := range t.parts {
+		if p.expr == nil {
+			sb.WriteString(encode(p.literal, true))
+			continue
+		}
+		if err := p.expr.expand(&sb, vars); err != nil {
+			return "", fmt.Errorf("failed to expand %q: %v", t.raw, err)
+		}
+	}
+	return sb.String(), nil
+}
+
+func (e *expression) expand(sb *strings.Builder, vars map[string]interface{}) error {
+	first := true
+
+	for _, v := range e.vars {
+		value, ok := vars[v.name]
+		if !ok || value == nil {
+			continue
+		}
+
+		var out string
+		var defined bool
+		var err error
+
+		switch value := value.(type) {
+		case string:
+			out, defined = e.expandString(v, value), true
+		case []string:
+			out, defined, err = e.expandList(v, value)
+		case map[string]string:
+			out, defined, err = e.expandMap(v, value)
+		default:
+			return fmt.Errorf("variable %q has unsupported type %T", v.name, value)
+		}
+		if err != nil {
+			return err
+		}
+		if !defined {
+			continue
+		}
+
+		if first {
+			sb.WriteString(e.op.first)
+			first = false
+		} else {
+			sb.WriteString(e.op.sep)
+		}
+		sb.WriteString(out)
+	}
+	return nil
+}
+
+func (e *expression) expandString(v varspec, value string) string {
+	if v.prefix > 0 && utf8.RuneCountInString(value) > v.prefix {
+		value = string([]rune(value)[:v.prefix])
+	}
+
+	if !e.op.named {
+		return encode(value, e.op.reserved)
+	}
+	if value == "" {
+		return v.name + e.op.ifEmpty
+	}
+	return v.name + "=" + encode(value, e.op.reserved)
+}
+
+func (e *expression) expandList(v varspec, values []string) (string, bool, error) {
+	if len(values) == 0 {
+		return "", false, nil
+	}
+	if v.prefix > 0 {
+		return "", false, fmt.Errorf("prefix modifier cannot be applied to list %q", v.name)
+	}
+
+	encoded := make([]string, len(values))
+	for i, value := range values {
+		encoded[i] = encode(value, e.op.reserved)
+	}
+
+	if !v.explode {
+		joined := strings.Join(encoded, ",")
+		if e.op.named {
+			return v.name + "=" + joined, true, nil
+		}
+		return joined, true, nil
+	}
+
+	if e.op.named {
+		for i, value := range encoded {
+			if value == "" {
+				encoded[i] = v.name + e.op.ifEmpty
+			} else {
+				encoded[i] = v.name + "=" + value
+			}
+		}
+	}
+	return strings.Join(encoded, e.op.sep), true, nil
+}
+
+func (e *expression) expandMap(v varspec, values map[string]string) (string, bool, error) {
+	if len(values) == 0 {
+		return "", false, nil
+	}
+	if v.prefix > 0 {
+		return "", false, fmt.Errorf("prefix modifier cannot be applied to associative array %q", v.name)
+	}
+
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		key := encode(k, e.op.reserved)
+		value := encode(values[k], e.op.reserved)
+
+		switch {
+		case !v.explode:
+			pairs = append(pairs, key+","+value)
+		case e.op.named && value == "":
+			pairs = append(pairs, key+e.op.ifEmpty)
+		default:
+			pairs = append(pairs, key+"="+value)
+		}
+	}
+
+	if !v.explode {
+		joined := strings.Join(pairs, ",")
+		if e.op.named {
+			return v.name + "=" + joined, true, nil
+		}
+		return joined, true, nil
+	}
+	return strings.Join(pairs, e.op.sep), true, nil
+}
+
+// encode percent-encodes s. Unreserved characters are always kept; with
+// reserved set, reserved characters and existing pct-encoded triplets are
+// kept too.
+func encode(s string, reserved bool) string {
+	var sb strings.Builder
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case isUnreserved(c):
+			sb.WriteByte(c)
+		case reserved && isReserved(c):
+			sb.WriteByte(c)
+		case reserved && c == '%' && i+2 < len(s) && isHex(s[i+1]) && isHex(s[i+2]):
+			sb.WriteString(s[i : i+3])
+			i += 2
+		default:
+			fmt.Fprintf(&sb, "%%%02X", c)
+		}
+	}
+	return sb.String()
+}
+
+func isAlpha(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isHex(c byte) bool {
+	return isDigit(c) || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+}
+
+func isUnreserved(c byte) bool {
+	return isAlpha(c) || isDigit(c) || c == '-' || c == '.' || c == '_' || c == '~'
+}
+
+func isReserved(c byte) bool {
+	return strings.IndexByte(":/?#[]@!$&'()*+,;=", c) >= 0
+}
@@ -0,0 +1,303 @@
+package uritemplate
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// rfcVars are the example variables of RFC 6570 section 3.2
+var rfcVars = map[string]interface{}{
+	"count":      []string{"one", "two", "three"},
+	"dom":        []string{"example", "com"},
+	"dub":        "me/too",
+	"hello":      "Hello World!",
+	"half":       "50%",
+	"var":        "value",
+	"who":        "fred",
+	"base":       "http://example.com/home/",
+	"path":       "/foo/bar",
+	"list":       []string{"red", "green", "blue"},
+	"keys":       map[string]string{"semi": ";", "dot": ".", "comma": ","},
+	"v":          "6",
+	"x":          "1024",
+	"y":          "768",
+	"empty":      "",
+	"empty_keys": map[string]string{},
+	"undef":      nil,
+}
+
+// The examples of RFC 6570 section 3.2. The RFC leaves the order of
+// associative array members open; Expand uses key order, so the keys
+// examples list comma, dot, semi.
+func TestExpandRFCExamples(t *testing.T) {
+	tests := []struct {
+		template string
+		want     string
+	}{
+		// 3.2.1 Variable Expansion
+		{"{count}", "one,two,three"},
+		{"{count*}", "one,two,three"},
+		{"{/count}", "/one,two,three"},
+		{"{/count*}", "/one/two/three"},
+		{"{;count}", ";count=one,two,three"},
+		{"{;count*}", ";count=one;count=two;count=three"},
+		{"{?count}", "?count=one,two,three"},
+		{"{?count*}", "?count=one&count=two&count=three"},
+		{"{&count*}", "&count=one&count=two&count=three"},
+
+		// 3.2.2 Simple String Expansion
+		{"{var}", "value"},
+		{"{hello}", "Hello%20World%21"},
+		{"{half}", "50%25"},
+		{"O{empty}X", "OX"},
+		{"O{undef}X", "OX"},
+		{"{x,y}", "1024,768"},
+		{"{x,hello,y}", "1024,Hello%20World%21,768"},
+		{"?{x,empty}", "?1024,"},
+		{"?{x,undef}", "?1024"},
+		{"?{undef,y}", "?768"},
+		{"{var:3}", "val"},
+		{"{var:30}", "value"},
+		{"{list}", "red,green,blue"},
+		{"{list*}", "red,green,blue"},
+		{"{keys}", "comma,%2C,dot,.,semi,%3B"},
+		{"{keys*}", "comma=%2C,dot=.,semi=%3B"},
+
+		// 3.2.3 Reserved Expansion
+		{"{+var}", "value"},
+		{"{+hello}", "Hello%20World!"},
+		{"{+half}", "50%25"},
+		{"{base}index", "http%3A%2F%2Fexample.com%2Fhome%2Findex"},
+		{"{+base}index", "http://example.com/home/index"},
+		{"O{+empty}X", "OX"},
+		{"O{+undef}X", "OX"},
+		{"{+path}/here", "/foo/bar/here"},
+		{"here?ref={+path}", "here?ref=/foo/bar"},
+		{"up{+path}{var}/here", "up/foo/barvalue/here"},
+		{"{+x,hello,y}", "1024,Hello%20World!,768"},
+		{"{+path,x}/here", "/foo/bar,1024/here"},
+		{"{+path:6}/here", "/foo/b/here"},
+		{"{+list}", "red,green,blue"},
+		{"{+list*}", "red,green,blue"},
+		{"{+keys}", "comma,,,dot,.,semi,;"},
+		{"{+keys*}", "comma=,,dot=.,semi=;"},
+
+		// 3.2.4 Fragment Expansion
+		{"{#var}", "#value"},
+		{"{#hello}", "#Hello%20World!"},
+		{"{#half}", "#50%25"},
+		{"foo{#empty}", "foo#"},
+		{"foo{#undef}", "foo"},
+		{"{#x,hello,y}", "#1024,Hello%20World!,768"},
+		{"{#path,x}/here", "#/foo/bar,1024/here"},
+		{"{#path:6}/here", "#/foo/b/here"},
+		{"{#list}", "#red,green,blue"},
+		{"{#list*}", "#red,green,blue"},
+		{"{#keys}", "#comma,,,dot,.,semi,;"},
+		{"{#keys*}", "#comma=,,dot=.,semi=;"},
+
+		// 3.2.5 Label Expansion with Dot-Prefix
+		{"{.who}", ".fred"},
+		{"{.who,who}", ".fred.fred"},
+		{"{.half,who}", ".50%25.fred"},
+		{"www{.dom*}", "www.example.com"},
+		{"X{.var}", "X.value"},
+		{"X{.empty}", "X."},
+		{"X{.undef}", "X"},
+		{"X{.var:3}", "X.val"},
+		{"X{.list}", "X.red,green,blue"},
+		{"X{.list*}", "X.red.green.blue"},
+		{"X{.keys}", "X.comma,%2C,dot,.,semi,%3B"},
+		{"X{.keys*}", "X.comma=%2C.dot=..semi=%3B"},
+		{"X{.empty_keys}", "X"},
+		{"X{.empty_keys*}", "X"},
+
+		// 3.2.6 Path Segment Expansion
+		{"{/who}", "/fred"},
+		{"{/who,who}", "/fred/fred"},
+		{"{/half,who}", "/50%25/fred"},
+		{"{/who,dub}", "/fred/me%2Ftoo"},
+		{"{/var}", "/value"},
+		{"{/var,empty}", "/value/"},
+		{"{/var,undef}", "/value"},
+		{"{/var,x}/here", "/value/1024/here"},
+		{"{/var:1,var}", "/v/value"},
+		{"{/list}", "/red,green,blue"},
+		{"{/list*}", "/red/green/blue"},
+		{"{/list*,path:4}", "/red/green/blue/%2Ffoo"},
+		{"{/keys}", "/comma,%2C,dot,.,semi,%3B"},
+		{"{/keys*}", "/comma=%2C/dot=./semi=%3B"},
+
+		// 3.2.7 Path-Style Parameter Expansion
+		{"{;who}", ";who=fred"},
+		{"{;half}", ";half=50%25"},
+		{"{;empty}", ";empty"},
+		{"{;v,empty,who}", ";v=6;empty;who=fred"},
+		{"{;v,bar,who}", ";v=6;who=fred"},
+		{"{;x,y}", ";x=1024;y=768"},
+		{"{;x,y,empty}", ";x=1024;y=768;empty"},
+		{"{;x,y,undef}", ";x=1024;y=768"},
+		{"{;hello:5}", ";hello=Hello"},
+		{"{;list}", ";list=red,green,blue"},
+		{"{;list*}", ";list=red;list=green;list=blue"},
+		{"{;keys}", ";keys=comma,%2C,dot,.,semi,%3B"},
+		{"{;keys*}", ";comma=%2C;dot=.;semi=%3B"},
+
+		// 3.2.8 Form-Style Query Expansion
+		{"{?who}", "?who=fred"},
+		{"{?half}", "?half=50%25"},
+		{"{?x,y}", "?x=1024&y=768"},
+		{"{?x,y,empty}", "?x=1024&y=768&empty="},
+		{"{?x,y,undef}", "?x=1024&y=768"},
+		{"{?var:3}", "?var=val"},
+		{"{?list}", "?list=red,green,blue"},
+		{"{?list*}", "?list=red&list=green&list=blue"},
+		{"{?keys}", "?keys=comma,%2C,dot,.,semi,%3B"},
+		{"{?keys*}", "?comma=%2C&dot=.&semi=%3B"},
+
+		// 3.2.9 Form-Style Query Continuation
+		{"{&who}", "&who=fred"},
+		{"{&half}", "&half=50%25"},
+		{"?fixed=yes{&x}", "?fixed=yes&x=1024"},
+		{"{&x,y,empty}", "&x=1024&y=768&empty="},
+		{"{&var:3}", "&var=val"},
+		{"{&list}", "&list=red,green,blue"},
+		{"{&list*}", "&list=red&list=green&list=blue"},
+		{"{&keys}", "&keys=comma,%2C,dot,.,semi,%3B"},
+		{"{&keys*}", "&comma=%2C&dot=.&semi=%3B"},
+	}
+
+	for _, tt := range tests {
+		tmpl, err := Parse(tt.template)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.template, err)
+			continue
+		}
+		got, err := tmpl.Expand(rfcVars)
+		if err != nil {
+			t.Errorf("Expand(%q): %v", tt.template, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Expand(%q) = %q, want %q", tt.template, got, tt.want)
+		}
+	}
+}
+
+func TestExpandEncoding(t *testing.T) {
+	tests := []struct {
+		template string
+		vars     map[string]interface{}
+		want     string
+	}{
+		{"{v}", map[string]interface{}{"v": "ü"}, "%C3%BC"},
+		{"{v:1}", map[string]interface{}{"v": "üx"}, "%C3%BC"},
+		{"{+v}", map[string]interface{}{"v": "a%20b%zz"}, "a%20b%25zz"},
+		{"{v}", map[string]interface{}{"v": "a%20b"}, "a%2520b"},
+		{"file:///{+path}", map[string]interface{}{"path": "docs/read me.md"}, "file:///docs/read%20me.md"},
+		{"a b{v}", map[string]interface{}{"v": "c"}, "a%20bc"},
+		{"{?q*}", map[string]interface{}{"q": map[string]string{"a": ""}}, "?a="},
+		{"{;q*}", map[string]interface{}{"q": []string{"", "x"}}, ";q;q=x"},
+		{"{/empty*}", map[string]interface{}{"empty": []string{}}, ""},
+		{"{x,y}", nil, ""},
+	}
+
+	for _, tt := range tests {
+		tmpl, err := Parse(tt.template)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.template, err)
+			continue
+		}
+		got, err := tmpl.Expand(tt.vars)
+		if err != nil {
+			t.Errorf("Expand(%q): %v", tt.template, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Expand(%q) = %q, want %q", tt.template, got, tt.want)
+		}
+	}
+}
+
+func TestExpandErrors(t *testing.T) {
+	tests := []struct {
+		template string
+		vars     map[string]interface{}
+		want     string
+	}{
+		{"{list:3}", map[string]interface{}{"list": []string{"a"}}, "prefix modifier cannot be applied to list"},
+		{"{keys:3}", map[string]interface{}{"keys": map[string]string{"a": "b"}}, "prefix modifier cannot be applied to associative array"},
+		{"{n}", map[string]interface{}{"n": 42}, "unsupported type int"},
+	}
+
+	for _, tt := range tests {
+		tmpl, err := Parse(tt.template)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.template, err)
+			continue
+		}
+		_, err = tmpl.Expand(tt.vars)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Expand(%q) error = %v, want it to contain %q", tt.template, err, tt.want)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		template string
+		want     string
+	}{
+		{"{", "unclosed '{'"},
+		{"a{b", "unclosed '{'"},
+		{"}", "unmatched '}'"},
+		{"a}{b}", "unmatched '}'"},
+		{"{b}}", "unmatched '}'"},
+		{"{}", "empty expression"},
+		{"{=var}", "is reserved"},
+		{"{|var}", "is reserved"},
+		{"{var:0}", "invalid prefix modifier"},
+		{"{var:10000}", "invalid prefix modifier"},
+		{"{var:x}", "invalid prefix modifier"},
+		{"{a b}", "invalid variable name"},
+		{"{a..b}", "invalid variable name"},
+		{"{.a.}", "invalid variable name"},
+		{"{a%2}", "invalid variable name"},
+		{"{a,}", "invalid variable name"},
+		{"{+}", "invalid variable name"},
+	}
+
+	for _, tt := range tests {
+		_, err := Parse(tt.template)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Parse(%q) error = %v, want it to contain %q", tt.template, err, tt.want)
+		}
+	}
+}
+
+func TestVarnames(t *testing.T) {
+	tests := []struct {
+		template string
+		want     []string
+	}{
+		{"file:///static", nil},
+		{"db://{table}/{id}", []string{"table", "id"}},
+		{"{+path}{?lines*,path}", []string{"path", "lines"}},
+		{"{a.b,%41_1:3}", []string{"a.b", "%41_1"}},
+	}
+
+	for _, tt := range tests {
+		tmpl, err := Parse(tt.template)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.template, err)
+			continue
+		}
+		if got := tmpl.Varnames(); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Varnames(%q) = %q, want %q", tt.template, got, tt.want)
+		}
+		if got := tmpl.String(); got != tt.template {
+			t.Errorf("String() = %q, want %q", got, tt.template)
+		}
+	}
+}